@@ -0,0 +1,202 @@
+package alertmanager
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nikoksr/notify"
+)
+
+// These are the statuses of alerts and groups of alerts.
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+// defaultMaxBodyBytes is the maximum size of a request body that is accepted by default.
+const defaultMaxBodyBytes = 1 << 20
+
+// Payload is the body of a webhook notification sent by Alertmanager. Grafana unified alerting sends the same payload
+// with a few additional fields.
+//
+// See https://prometheus.io/docs/alerting/latest/configuration/#webhook_config.
+type Payload struct {
+	Version           string            `json:"version"`
+	GroupKey          string            `json:"groupKey"`
+	TruncatedAlerts   int               `json:"truncatedAlerts"`
+	Status            string            `json:"status"`
+	Receiver          string            `json:"receiver"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	ExternalURL       string            `json:"externalURL"`
+	Alerts            []Alert           `json:"alerts"`
+
+	// These fields are only set by Grafana.
+	OrgID   int64  `json:"orgId,omitempty"`
+	Title   string `json:"title,omitempty"`
+	State   string `json:"state,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Alert is a single alert of a Payload.
+type Alert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+
+	// These fields are only set by Grafana.
+	SilenceURL   string             `json:"silenceURL,omitempty"`
+	DashboardURL string             `json:"dashboardURL,omitempty"`
+	PanelURL     string             `json:"panelURL,omitempty"`
+	Values       map[string]float64 `json:"values,omitempty"`
+	ValueString  string             `json:"valueString,omitempty"`
+}
+
+// Firing returns the alerts of the payload that are firing.
+func (p *Payload) Firing() []Alert {
+	return p.alertsWithStatus(StatusFiring)
+}
+
+// Resolved returns the alerts of the payload that are resolved.
+func (p *Payload) Resolved() []Alert {
+	return p.alertsWithStatus(StatusResolved)
+}
+
+func (p *Payload) alertsWithStatus(status string) []Alert {
+	var alerts []Alert
+	for _, alert := range p.Alerts {
+		if alert.Status == status {
+			alerts = append(alerts, alert)
+		}
+	}
+
+	return alerts
+}
+
+// Severity returns the highest severity of the firing alerts of the payload, or of the resolved alerts if the payload
+// is resolved, so that a resolution reaches the same services as the alert did. The severity of an alert is read from
+// the label with the given name and parsed using notify.ParseSeverity. It returns notify.DefaultSeverity if no such
+// alert has a known severity.
+func (p *Payload) Severity(label string) notify.Severity {
+	alerts := p.Firing()
+	if p.Status == StatusResolved {
+		alerts = p.Resolved()
+	}
+
+	severity, found := notify.DefaultSeverity, false
+
+	for _, alert := range alerts {
+		s, err := notify.ParseSeverity(alert.Labels[label])
+		if err != nil {
+			continue
+		}
+		if !found || s > severity {
+			severity, found = s, true
+		}
+	}
+
+	return severity
+}
+
+// RenderFn renders a payload into the subject and message of a notification.
+type RenderFn func(payload *Payload) (subject, message string)
+
+// Options configure a Handler.
+type Options struct {
+	// Render renders the received payloads. Defaults to Render.
+	Render RenderFn
+
+	// SeverityLabel is the name of the alert label that holds the severity of an alert. The highest severity of the
+	// alerts of a payload is used as notify.Severity of the notification, see Payload.Severity. Defaults to
+	// "severity".
+	SeverityLabel string
+
+	// BearerToken, if set, is required in the Authorization header of every request. It matches the authorization
+	// setting of the http_config of an Alertmanager webhook receiver.
+	BearerToken string
+
+	// MaxBodyBytes is the maximum size of a request body. Defaults to 1 MiB.
+	MaxBodyBytes int64
+}
+
+// Handler is an http.Handler that receives the webhook notifications of Alertmanager and Grafana and forwards them to a
+// notify.Notifier, usually a *notify.Notify.
+type Handler struct {
+	notifier notify.Notifier
+	opts     Options
+}
+
+// Compile-time check to ensure Handler implements http.Handler.
+var _ http.Handler = (*Handler)(nil)
+
+// NewHandler returns a new Handler that forwards all alerts to the given notifier.
+func NewHandler(notifier notify.Notifier, opts Options) *Handler {
+	if opts.Render == nil {
+		opts.Render = Render
+	}
+	if opts.SeverityLabel == "" {
+		opts.SeverityLabel = "severity"
+	}
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = defaultMaxBodyBytes
+	}
+
+	return &Handler{
+		notifier: notifier,
+		opts:     opts,
+	}
+}
+
+// authorized reports whether the request carries the configured bearer token.
+func (h *Handler) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(h.opts.BearerToken)) == 1
+}
+
+// ServeHTTP decodes the payload of the request, renders it and sends it to the notifier. Failed sends are answered with
+// status 502, so that Alertmanager retries them.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if h.opts.BearerToken != "" && !h.authorized(r) {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	var payload Payload
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.opts.MaxBodyBytes)).Decode(&payload); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		http.Error(w, fmt.Sprintf("decode payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	subject, message := h.opts.Render(&payload)
+	ctx := notify.WithSeverity(r.Context(), payload.Severity(h.opts.SeverityLabel))
+
+	if err := h.notifier.Send(ctx, subject, message); err != nil {
+		http.Error(w, fmt.Sprintf("send notification: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
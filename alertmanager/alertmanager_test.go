@@ -0,0 +1,240 @@
+package alertmanager
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/notify"
+)
+
+const alertmanagerPayload = `{
+  "version": "4",
+  "groupKey": "{}:{alertname=\"HighLatency\"}",
+  "truncatedAlerts": 0,
+  "status": "firing",
+  "receiver": "dingtalk",
+  "groupLabels": {"alertname": "HighLatency", "service": "api"},
+  "commonLabels": {"alertname": "HighLatency", "service": "api"},
+  "commonAnnotations": {},
+  "externalURL": "http://alertmanager:9093",
+  "alerts": [
+    {
+      "status": "firing",
+      "labels": {"alertname": "HighLatency", "service": "api", "severity": "critical", "instance": "api-1"},
+      "annotations": {"summary": "p99 latency above 1s"},
+      "startsAt": "2024-03-06T12:00:00Z",
+      "endsAt": "0001-01-01T00:00:00Z",
+      "generatorURL": "http://prometheus:9090/graph",
+      "fingerprint": "a1"
+    },
+    {
+      "status": "resolved",
+      "labels": {"alertname": "HighLatency", "service": "api", "severity": "warning", "instance": "api-2"},
+      "annotations": {"description": "latency is back to normal"},
+      "startsAt": "2024-03-06T11:00:00Z",
+      "endsAt": "2024-03-06T11:30:00Z",
+      "generatorURL": "http://prometheus:9090/graph",
+      "fingerprint": "a2"
+    }
+  ]
+}`
+
+const grafanaPayload = `{
+  "receiver": "lark",
+  "status": "resolved",
+  "orgId": 1,
+  "alerts": [
+    {
+      "status": "resolved",
+      "labels": {"alertname": "DiskFull", "severity": "warning"},
+      "annotations": {},
+      "startsAt": "2024-03-06T11:00:00Z",
+      "endsAt": "2024-03-06T11:30:00Z",
+      "silenceURL": "http://grafana/silence",
+      "valueString": "[ var='A' value=91 ]"
+    }
+  ],
+  "groupLabels": {"alertname": "DiskFull"},
+  "commonLabels": {"alertname": "DiskFull"},
+  "title": "[RESOLVED] DiskFull",
+  "state": "ok",
+  "message": "resolved"
+}`
+
+// recorder is a notify.Notifier that records the last notification it was sent.
+type recorder struct {
+	subject  string
+	message  string
+	severity notify.Severity
+	err      error
+}
+
+func (r *recorder) Send(ctx context.Context, subject, message string) error {
+	r.subject, r.message = subject, message
+	r.severity, _ = notify.SeverityFromContext(ctx)
+
+	return r.err
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	rec := &recorder{}
+	serve(NewHandler(rec, Options{}), "", alertmanagerPayload)
+
+	require.Equal(t, "[FIRING:1] HighLatency api", rec.subject)
+	require.Equal(t, `Firing:
+- HighLatency: p99 latency above 1s
+  Labels: alertname=HighLatency, instance=api-1, service=api, severity=critical
+  Since: 2024-03-06T12:00:00Z
+  Source: http://prometheus:9090/graph
+
+Resolved:
+- HighLatency: latency is back to normal
+  Labels: alertname=HighLatency, instance=api-2, service=api, severity=warning
+  Since: 2024-03-06T11:00:00Z
+  Resolved at: 2024-03-06T11:30:00Z
+  Source: http://prometheus:9090/graph`, rec.message)
+	require.Equal(t, notify.SeverityCritical, rec.severity)
+
+	serve(NewHandler(rec, Options{}), "", grafanaPayload)
+
+	require.Equal(t, "[RESOLVED] DiskFull", rec.subject)
+	require.Contains(t, rec.message, "Values: [ var='A' value=91 ]")
+	require.Contains(t, rec.message, "Silence: http://grafana/silence")
+	require.Equal(t, notify.SeverityWarning, rec.severity)
+}
+
+func TestPayload_Severity(t *testing.T) {
+	t.Parallel()
+
+	alert := func(status, severity string) Alert {
+		return Alert{Status: status, Labels: map[string]string{"severity": severity}}
+	}
+
+	tests := []struct {
+		name    string
+		payload Payload
+		want    notify.Severity
+	}{
+		{
+			name: "Highest severity of firing alerts",
+			payload: Payload{Status: StatusFiring, Alerts: []Alert{
+				alert(StatusFiring, "warning"),
+				alert(StatusFiring, "error"),
+			}},
+			want: notify.SeverityError,
+		},
+		{
+			name: "Resolved alerts are ignored",
+			payload: Payload{Status: StatusFiring, Alerts: []Alert{
+				alert(StatusFiring, "warning"),
+				alert(StatusResolved, "critical"),
+			}},
+			want: notify.SeverityWarning,
+		},
+		{
+			name: "Resolved payload",
+			payload: Payload{Status: StatusResolved, Alerts: []Alert{
+				alert(StatusResolved, "warning"),
+				alert(StatusResolved, "critical"),
+			}},
+			want: notify.SeverityCritical,
+		},
+		{
+			name:    "Unknown severity",
+			payload: Payload{Status: StatusFiring, Alerts: []Alert{alert(StatusFiring, "")}},
+			want:    notify.DefaultSeverity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, tt.payload.Severity("severity"))
+		})
+	}
+}
+
+// serve sends the given body to the handler and returns the response.
+func serve(handler http.Handler, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/alerts", strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		opts       Options
+		notifyErr  error
+		token      string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "Success",
+			body:       alertmanagerPayload,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Valid token",
+			opts:       Options{BearerToken: "secret"},
+			token:      "secret",
+			body:       alertmanagerPayload,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Invalid token",
+			opts:       Options{BearerToken: "secret"},
+			token:      "wrong",
+			body:       alertmanagerPayload,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Invalid payload",
+			body:       `{"alerts": [`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Payload too large",
+			opts:       Options{MaxBodyBytes: 16},
+			body:       alertmanagerPayload,
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "Failed send",
+			notifyErr:  errors.New("provider down"),
+			body:       alertmanagerPayload,
+			wantStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := serve(NewHandler(&recorder{err: tt.notifyErr}, tt.opts), tt.token, tt.body)
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	NewHandler(&recorder{}, Options{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/alerts", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -0,0 +1,39 @@
+/*
+Package alertmanager provides an http.Handler that receives the webhook notifications of Prometheus Alertmanager and
+Grafana unified alerting, and forwards them to notification services.
+
+It allows using any of the services of this library as a receiver of Alertmanager, including those that Alertmanager
+does not support natively, e.g. DingTalk, Lark or WeChat.
+
+Usage:
+
+	package main
+
+	import (
+	    "log"
+	    "net/http"
+
+	    "github.com/nikoksr/notify"
+	    "github.com/nikoksr/notify/alertmanager"
+	    "github.com/nikoksr/notify/service/dingding"
+	)
+
+	func main() {
+	    // Create the services that should receive the alerts.
+	    dingDingService := dingding.New(&dingding.Config{Token: "token", Secret: "secret"})
+
+	    n := notify.New()
+	    n.UseServices(dingDingService)
+
+	    // Serve the handler and point the webhook_configs of an Alertmanager receiver at it:
+	    //
+	    //   receivers:
+	    //     - name: dingtalk
+	    //       webhook_configs:
+	    //         - url: http://localhost:8080/alerts
+	    http.Handle("/alerts", alertmanager.NewHandler(n, alertmanager.Options{}))
+
+	    log.Fatal(http.ListenAndServe(":8080", nil))
+	}
+*/
+package alertmanager
@@ -0,0 +1,108 @@
+package alertmanager
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Render is the default RenderFn. It renders the subject like the default title of Alertmanager, e.g.
+// "[FIRING:2] HighLatency api", and lists the firing and resolved alerts with their summary, labels and links in the
+// message. Grafana payloads keep the title that was rendered by Grafana.
+func Render(payload *Payload) (subject, message string) {
+	return renderSubject(payload), renderMessage(payload)
+}
+
+// sortedPairs returns the given labels as sorted key=value pairs.
+func sortedPairs(labels map[string]string) []string {
+	pairs := make([]string, 0, len(labels))
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		pairs = append(pairs, key+"="+labels[key])
+	}
+
+	return pairs
+}
+
+func renderSubject(payload *Payload) string {
+	if payload.Title != "" {
+		return payload.Title
+	}
+
+	var b strings.Builder
+	b.WriteString("[" + strings.ToUpper(payload.Status))
+	if payload.Status == StatusFiring {
+		fmt.Fprintf(&b, ":%d", len(payload.Firing()))
+	}
+	b.WriteString("]")
+
+	labels := payload.GroupLabels
+	if len(labels) == 0 {
+		labels = map[string]string{"alertname": payload.CommonLabels["alertname"]}
+	}
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		if labels[key] != "" {
+			b.WriteString(" " + labels[key])
+		}
+	}
+
+	return b.String()
+}
+
+func renderMessage(payload *Payload) string {
+	var sections []string
+
+	if firing := payload.Firing(); len(firing) > 0 {
+		sections = append(sections, renderAlerts("Firing", firing))
+	}
+	if resolved := payload.Resolved(); len(resolved) > 0 {
+		sections = append(sections, renderAlerts("Resolved", resolved))
+	}
+	if payload.TruncatedAlerts > 0 {
+		sections = append(sections, fmt.Sprintf("%d more alerts were truncated.", payload.TruncatedAlerts))
+	}
+
+	return strings.Join(sections, "\n\n")
+}
+
+func renderAlerts(title string, alerts []Alert) string {
+	var b strings.Builder
+	b.WriteString(title + ":")
+
+	for _, alert := range alerts {
+		b.WriteString("\n- " + alert.Labels["alertname"])
+
+		description := alert.Annotations["summary"]
+		if description == "" {
+			description = alert.Annotations["description"]
+		}
+		if description != "" {
+			b.WriteString(": " + description)
+		}
+
+		if pairs := sortedPairs(alert.Labels); len(pairs) > 0 {
+			b.WriteString("\n  Labels: " + strings.Join(pairs, ", "))
+		}
+		if alert.ValueString != "" {
+			b.WriteString("\n  Values: " + alert.ValueString)
+		}
+		if !alert.StartsAt.IsZero() {
+			b.WriteString("\n  Since: " + alert.StartsAt.UTC().Format(time.RFC3339))
+		}
+		if alert.Status == StatusResolved && !alert.EndsAt.IsZero() {
+			b.WriteString("\n  Resolved at: " + alert.EndsAt.UTC().Format(time.RFC3339))
+		}
+		if alert.GeneratorURL != "" {
+			b.WriteString("\n  Source: " + alert.GeneratorURL)
+		}
+		if alert.DashboardURL != "" {
+			b.WriteString("\n  Dashboard: " + alert.DashboardURL)
+		}
+		if alert.SilenceURL != "" {
+			b.WriteString("\n  Silence: " + alert.SilenceURL)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,287 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen signals that a send was rejected by a CircuitBreaker without contacting the wrapped service.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+// These are the states of a CircuitBreaker.
+const (
+	// CircuitClosed is the normal state: all sends are passed through to the wrapped service.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen is the state after too many consecutive failures: all sends are rejected until the cool-down ends.
+	CircuitOpen
+
+	// CircuitHalfOpen is the state after the cool-down: a limited number of probe sends are passed through to find out
+	// whether the wrapped service recovered.
+	CircuitHalfOpen
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+
+	return "unknown"
+}
+
+// CircuitOpenError is returned by a CircuitBreaker for every send it rejects. It wraps ErrCircuitOpen and LastErr, so
+// that e.g. IsRetryable and the ReceiverErrors of the last error are still available.
+type CircuitOpenError struct {
+	// Until is the time at which the circuit breaker lets the next probe send through.
+	Until time.Time
+
+	// LastErr is the error that caused the circuit breaker to open.
+	LastErr error
+}
+
+// Error returns a description of the error.
+func (e *CircuitOpenError) Error() string {
+	return ErrCircuitOpen.Error() + " until " + e.Until.Format(time.RFC3339)
+}
+
+// Unwrap returns ErrCircuitOpen and the error that caused the circuit breaker to open, if any.
+func (e *CircuitOpenError) Unwrap() []error {
+	if e.LastErr == nil {
+		return []error{ErrCircuitOpen}
+	}
+
+	return []error{ErrCircuitOpen, e.LastErr}
+}
+
+// CircuitBreakerOptions configure a CircuitBreaker. Zero values are replaced by their documented defaults.
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failed sends after which the circuit opens. Defaults to 5.
+	FailureThreshold int
+
+	// SuccessThreshold is the number of successful probe sends in the half-open state after which the circuit closes
+	// again. Defaults to 1.
+	SuccessThreshold int
+
+	// HalfOpenMaxRequests is the maximum number of probe sends that are in flight at the same time in the half-open
+	// state. Sends above this limit are rejected. Defaults to 1.
+	HalfOpenMaxRequests int
+
+	// CoolDown is the time the circuit stays open before it lets probe sends through. Defaults to 30 seconds.
+	CoolDown time.Duration
+
+	// IsFailure decides whether an error returned by the wrapped service counts as a failure. Errors that don't count
+	// are neither failures nor successes. By default, all errors except context.Canceled count as failures.
+	IsFailure func(err error) bool
+
+	// Fallback, if set, receives all messages that are rejected while the circuit is open. If the fallback succeeds,
+	// the send is reported as successful.
+	Fallback Notifier
+
+	// OnStateChange, if set, is called on every state change. It's called synchronously, but never while the circuit
+	// breaker is locked, so it's safe to call the circuit breaker's methods from it.
+	OnStateChange func(from, to CircuitState)
+}
+
+// CircuitBreaker is a Notifier that wraps another Notifier and stops calling it after it failed repeatedly. This keeps
+// sends fast while a provider is down, instead of waiting for every single request to fail.
+//
+// The circuit starts closed. After FailureThreshold consecutive failures it opens and rejects all sends with a
+// CircuitOpenError, or routes them to the Fallback. Once the CoolDown has passed, it becomes half-open and lets probe
+// sends through; it closes after SuccessThreshold successful probes and opens again on the first failed one.
+//
+// A CircuitBreaker is safe for concurrent use.
+type CircuitBreaker struct {
+	service Notifier
+	opts    CircuitBreakerOptions
+	now     func() time.Time
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int
+	successes int
+	probes    int
+	openedAt  time.Time
+	lastErr   error
+	changes   [][2]CircuitState
+}
+
+// Compile-time check to ensure CircuitBreaker implements Notifier.
+var _ Notifier = (*CircuitBreaker)(nil)
+
+// NewCircuitBreaker returns a new CircuitBreaker that wraps the given service.
+func NewCircuitBreaker(service Notifier, opts CircuitBreakerOptions) *CircuitBreaker {
+	if opts.FailureThreshold < 1 {
+		opts.FailureThreshold = 5
+	}
+	if opts.SuccessThreshold < 1 {
+		opts.SuccessThreshold = 1
+	}
+	if opts.HalfOpenMaxRequests < 1 {
+		opts.HalfOpenMaxRequests = 1
+	}
+	if opts.CoolDown <= 0 {
+		opts.CoolDown = 30 * time.Second
+	}
+	if opts.IsFailure == nil {
+		opts.IsFailure = func(err error) bool {
+			return !errors.Is(err, context.Canceled)
+		}
+	}
+
+	return &CircuitBreaker{
+		service: service,
+		opts:    opts,
+		now:     time.Now,
+	}
+}
+
+// State returns the current state of the circuit. An open circuit whose cool-down has passed is reported as half-open.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.unlock()
+
+	b.refresh()
+
+	return b.state
+}
+
+// Reset closes the circuit and clears all counters.
+func (b *CircuitBreaker) Reset() {
+	b.mu.Lock()
+	defer b.unlock()
+
+	b.setState(CircuitClosed)
+}
+
+// Send sends the message to the wrapped service, unless the circuit is open. While the circuit is open, the message is
+// sent to the fallback if there is one; otherwise a CircuitOpenError is returned.
+func (b *CircuitBreaker) Send(ctx context.Context, subject, message string) error {
+	if err := b.allow(); err != nil {
+		if b.opts.Fallback == nil {
+			return err
+		}
+
+		if fallbackErr := b.opts.Fallback.Send(ctx, subject, message); fallbackErr != nil {
+			return errors.Join(err, fallbackErr)
+		}
+
+		return nil
+	}
+
+	err := b.service.Send(ctx, subject, message)
+	b.record(err)
+
+	return err
+}
+
+// unlock unlocks the circuit breaker and then reports all state changes that happened while it was locked.
+func (b *CircuitBreaker) unlock() {
+	changes := b.changes
+	b.changes = nil
+	b.mu.Unlock()
+
+	if b.opts.OnStateChange == nil {
+		return
+	}
+	for _, change := range changes {
+		b.opts.OnStateChange(change[0], change[1])
+	}
+}
+
+// setState moves the circuit into the given state and resets the counters of that state. The caller must hold the
+// lock.
+func (b *CircuitBreaker) setState(state CircuitState) {
+	switch state {
+	case CircuitClosed:
+		b.failures = 0
+		b.lastErr = nil
+	case CircuitOpen:
+		b.openedAt = b.now()
+	case CircuitHalfOpen:
+	}
+	b.successes = 0
+	b.probes = 0
+
+	if b.state != state {
+		b.changes = append(b.changes, [2]CircuitState{b.state, state})
+		b.state = state
+	}
+}
+
+// refresh moves an open circuit into the half-open state once its cool-down has passed. The caller must hold the lock.
+func (b *CircuitBreaker) refresh() {
+	if b.state == CircuitOpen && !b.now().Before(b.openedAt.Add(b.opts.CoolDown)) {
+		b.setState(CircuitHalfOpen)
+	}
+}
+
+// allow reports whether a send may pass through to the wrapped service. It returns a CircuitOpenError if not.
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.unlock()
+
+	b.refresh()
+
+	switch b.state {
+	case CircuitClosed:
+		return nil
+	case CircuitHalfOpen:
+		if b.probes < b.opts.HalfOpenMaxRequests {
+			b.probes++
+			return nil
+		}
+	case CircuitOpen:
+	}
+
+	return &CircuitOpenError{
+		Until:   b.openedAt.Add(b.opts.CoolDown),
+		LastErr: b.lastErr,
+	}
+}
+
+// record updates the circuit with the result of a send that was passed through to the wrapped service.
+func (b *CircuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	failed := err != nil && b.opts.IsFailure(err)
+
+	switch b.state {
+	case CircuitClosed:
+		if err == nil {
+			b.failures = 0
+		} else if failed {
+			b.failures++
+			if b.failures >= b.opts.FailureThreshold {
+				b.lastErr = err
+				b.setState(CircuitOpen)
+			}
+		}
+	case CircuitHalfOpen:
+		if b.probes > 0 {
+			b.probes--
+		}
+		if failed {
+			b.lastErr = err
+			b.setState(CircuitOpen)
+		} else if err == nil {
+			b.successes++
+			if b.successes >= b.opts.SuccessThreshold {
+				b.setState(CircuitClosed)
+			}
+		}
+	case CircuitOpen:
+		// The circuit was opened by another send while this one was in flight; nothing to do.
+	}
+}
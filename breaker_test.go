@@ -0,0 +1,150 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// toggleNotifier is a Notifier whose sends fail until it's switched to healthy.
+type toggleNotifier struct {
+	healthy atomic.Bool
+	calls   atomic.Int32
+}
+
+func (t *toggleNotifier) Send(context.Context, string, string) error {
+	t.calls.Add(1)
+	if t.healthy.Load() {
+		return nil
+	}
+
+	return errors.New("provider down")
+}
+
+// recordingNotifier is a Notifier that records the subjects it was sent.
+type recordingNotifier struct {
+	subjects []string
+}
+
+func (r *recordingNotifier) Send(_ context.Context, subject, _ string) error {
+	r.subjects = append(r.subjects, subject)
+	return nil
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	service := &toggleNotifier{}
+
+	var changes []string
+	breaker := NewCircuitBreaker(service, CircuitBreakerOptions{
+		FailureThreshold: 2,
+		CoolDown:         time.Minute,
+		OnStateChange: func(from, to CircuitState) {
+			changes = append(changes, from.String()+"->"+to.String())
+		},
+	})
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker.now = func() time.Time { return now }
+
+	// Two consecutive failures open the circuit.
+	require.Error(t, breaker.Send(ctx, "subject", "message"))
+	require.Equal(t, CircuitClosed, breaker.State())
+	require.Error(t, breaker.Send(ctx, "subject", "message"))
+	require.Equal(t, CircuitOpen, breaker.State())
+
+	// While open, sends are rejected without calling the service.
+	err := breaker.Send(ctx, "subject", "message")
+	require.ErrorIs(t, err, ErrCircuitOpen)
+
+	var openErr *CircuitOpenError
+	require.ErrorAs(t, err, &openErr)
+	require.Equal(t, now.Add(time.Minute), openErr.Until)
+	require.EqualError(t, openErr.LastErr, "provider down")
+	require.Equal(t, int32(2), service.calls.Load())
+
+	// The cause stays reachable, e.g. for retry decisions.
+	lastErr := NewReceiverError("test", "receiver", errors.New("provider down"))
+	lastErr.Retryable = true
+	openErr = &CircuitOpenError{Until: now, LastErr: lastErr}
+	require.ErrorIs(t, openErr, ErrCircuitOpen)
+	require.True(t, IsRetryable(openErr))
+	var receiverErr *ReceiverError
+	require.ErrorAs(t, openErr, &receiverErr)
+
+	// After the cool-down, a failed probe opens the circuit again.
+	now = now.Add(time.Minute)
+	require.Equal(t, CircuitHalfOpen, breaker.State())
+	require.EqualError(t, breaker.Send(ctx, "subject", "message"), "provider down")
+	require.Equal(t, CircuitOpen, breaker.State())
+
+	// A successful probe closes it.
+	now = now.Add(time.Minute)
+	service.healthy.Store(true)
+	require.NoError(t, breaker.Send(ctx, "subject", "message"))
+	require.Equal(t, CircuitClosed, breaker.State())
+
+	require.Equal(t, []string{
+		"closed->open",
+		"open->half-open",
+		"half-open->open",
+		"open->half-open",
+		"half-open->closed",
+	}, changes)
+}
+
+func TestCircuitBreaker_Fallback(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fallback := &recordingNotifier{}
+	breaker := NewCircuitBreaker(&toggleNotifier{}, CircuitBreakerOptions{
+		FailureThreshold: 1,
+		Fallback:         fallback,
+	})
+
+	require.Error(t, breaker.Send(ctx, "first", "message"))
+	require.NoError(t, breaker.Send(ctx, "second", "message"))
+	require.Equal(t, []string{"second"}, fallback.subjects)
+
+	breaker = NewCircuitBreaker(&toggleNotifier{}, CircuitBreakerOptions{
+		FailureThreshold: 1,
+		Fallback:         newFailingNotifier(),
+	})
+
+	require.Error(t, breaker.Send(ctx, "first", "message"))
+	err := breaker.Send(ctx, "second", "message")
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	require.ErrorContains(t, err, "send failed")
+}
+
+func TestCircuitBreaker_IsFailure(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	canceled := &contextNotifier{}
+	breaker := NewCircuitBreaker(canceled, CircuitBreakerOptions{FailureThreshold: 1})
+
+	for range 3 {
+		require.ErrorIs(t, breaker.Send(ctx, "subject", "message"), context.Canceled)
+	}
+	require.Equal(t, CircuitClosed, breaker.State())
+
+	breaker.Reset()
+	require.Equal(t, CircuitClosed, breaker.State())
+}
+
+// contextNotifier is a Notifier that returns the error of the given context.
+type contextNotifier struct{}
+
+func (contextNotifier) Send(ctx context.Context, _, _ string) error {
+	return ctx.Err()
+}
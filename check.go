@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrCheckFailed signals that at least one service failed its health check.
+var ErrCheckFailed = errors.New("health check failed")
+
+// Checker is an optional interface that notification services can implement to verify their credentials and the
+// connectivity to their provider without sending a message.
+//
+//	E.g. for telegram.Telegram it calls the getMe endpoint of the Bot API.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckResult holds the outcome of the health check of a single service.
+type CheckResult struct {
+	// Name is the name the service is registered under, see ServiceInfo.
+	Name string
+
+	// Service is the checked service.
+	Service Notifier
+
+	// Supported reports whether the service implements the Checker interface. Services that don't are reported as
+	// healthy.
+	Supported bool
+
+	// Err is the error returned by the health check. It's nil if the service is healthy.
+	Err error
+}
+
+// Healthy reports whether the service passed its health check.
+func (r CheckResult) Healthy() bool {
+	return r.Err == nil
+}
+
+// check runs the health checks of all services concurrently.
+func (n *Notify) check(ctx context.Context) ([]CheckResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	entries := n.enabledEntries(allSeverities)
+	results := make([]CheckResult, len(entries))
+
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		results[i].Name = entry.name
+		results[i].Service = entry.service
+
+		checker, ok := entry.service.(Checker)
+		if !ok {
+			continue
+		}
+		results[i].Supported = true
+
+		wg.Go(func() {
+			results[i].Err = checker.Check(ctx)
+		})
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+
+	var err error
+	if len(errs) > 0 {
+		err = errors.Join(append([]error{ErrCheckFailed}, errs...)...)
+	}
+
+	return results, err
+}
+
+// Check runs the health checks of all enabled services that implement the Checker interface and returns a result for
+// every service. The returned error is non-nil if at least one of the checks failed; it wraps ErrCheckFailed and the
+// errors of all failed checks. A disabled Notify instance still runs its checks.
+func (n *Notify) Check(ctx context.Context) ([]CheckResult, error) {
+	return n.check(ctx)
+}
+
+// Check runs the health checks of all services that implement the Checker interface and returns a result for every
+// service.
+func Check(ctx context.Context) ([]CheckResult, error) {
+	return std.Check(ctx)
+}
@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// checkingNotifier is a Notifier that implements the Checker interface.
+type checkingNotifier struct {
+	err error
+}
+
+func (c *checkingNotifier) Send(context.Context, string, string) error {
+	return nil
+}
+
+func (c *checkingNotifier) Check(context.Context) error {
+	return c.err
+}
+
+func TestNotifyCheck(t *testing.T) {
+	t.Parallel()
+
+	n := New()
+	results, err := n.Check(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	healthy := &checkingNotifier{}
+	unhealthy := &checkingNotifier{err: errors.New("token expired")}
+	unsupported := newFailingNotifier()
+
+	n.UseServices(healthy)
+	require.NoError(t, n.UseNamedService("unhealthy", unhealthy))
+	n.UseServices(unsupported)
+
+	results, err = n.Check(context.Background())
+	require.ErrorIs(t, err, ErrCheckFailed)
+	require.ErrorIs(t, err, unhealthy.err)
+	require.Len(t, results, 3)
+
+	require.Equal(t, "*notify.checkingNotifier", results[0].Name)
+	require.Same(t, healthy, results[0].Service)
+	require.True(t, results[0].Supported)
+	require.True(t, results[0].Healthy())
+
+	require.Equal(t, "unhealthy", results[1].Name)
+	require.Same(t, unhealthy, results[1].Service)
+	require.True(t, results[1].Supported)
+	require.False(t, results[1].Healthy())
+
+	require.Same(t, unsupported, results[2].Service)
+	require.False(t, results[2].Supported)
+	require.True(t, results[2].Healthy())
+}
@@ -0,0 +1,241 @@
+// Command notifyd runs a notification relay server. It forwards the messages that clients post to its REST API to the
+// notification services defined in its configuration file. See package relay for the format of the configuration and
+// the API.
+//
+// Usage:
+//
+//	notifyd -config /etc/notifyd/config.json -addr :8080
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/nikoksr/notify"
+	"github.com/nikoksr/notify/relay"
+	"github.com/nikoksr/notify/service/bark"
+	"github.com/nikoksr/notify/service/discord"
+	httpsvc "github.com/nikoksr/notify/service/http"
+	"github.com/nikoksr/notify/service/mail"
+	"github.com/nikoksr/notify/service/msteams"
+	"github.com/nikoksr/notify/service/pushover"
+	"github.com/nikoksr/notify/service/slack"
+	"github.com/nikoksr/notify/service/telegram"
+)
+
+// shutdownTimeout is how long in-flight requests get to finish on shutdown.
+const shutdownTimeout = 30 * time.Second
+
+// decode decodes the given settings into a new value of type T and rejects unknown fields.
+func decode[T any](settings json.RawMessage) (T, error) {
+	var v T
+	if len(settings) == 0 {
+		return v, errors.New("missing settings")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(settings))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&v); err != nil {
+		return v, fmt.Errorf("decode settings: %w", err)
+	}
+
+	return v, nil
+}
+
+// factories returns the factories of all service types supported by notifyd.
+func factories() map[string]relay.Factory {
+	return map[string]relay.Factory{
+		"slack": func(settings json.RawMessage) (notify.Notifier, error) {
+			s, err := decode[struct {
+				Token    string   `json:"token"`
+				Channels []string `json:"channels"`
+			}](settings)
+			if err != nil {
+				return nil, err
+			}
+
+			svc := slack.New(s.Token)
+			svc.AddReceivers(s.Channels...)
+
+			return svc, nil
+		},
+		"telegram": func(settings json.RawMessage) (notify.Notifier, error) {
+			s, err := decode[struct {
+				Token string  `json:"token"`
+				Chats []int64 `json:"chats"`
+			}](settings)
+			if err != nil {
+				return nil, err
+			}
+
+			svc, err := telegram.New(s.Token)
+			if err != nil {
+				return nil, err
+			}
+			svc.AddReceivers(s.Chats...)
+
+			return svc, nil
+		},
+		"discord": func(settings json.RawMessage) (notify.Notifier, error) {
+			s, err := decode[struct {
+				BotToken string   `json:"bot_token"`
+				Channels []string `json:"channels"`
+			}](settings)
+			if err != nil {
+				return nil, err
+			}
+
+			svc := discord.New()
+			if err = svc.AuthenticateWithBotToken(s.BotToken); err != nil {
+				return nil, err
+			}
+			svc.AddReceivers(s.Channels...)
+
+			return svc, nil
+		},
+		"msteams": func(settings json.RawMessage) (notify.Notifier, error) {
+			s, err := decode[struct {
+				Webhooks []string `json:"webhooks"`
+			}](settings)
+			if err != nil {
+				return nil, err
+			}
+
+			svc := msteams.New()
+			svc.AddReceivers(s.Webhooks...)
+
+			return svc, nil
+		},
+		"pushover": func(settings json.RawMessage) (notify.Notifier, error) {
+			s, err := decode[struct {
+				Token      string   `json:"token"`
+				Recipients []string `json:"recipients"`
+			}](settings)
+			if err != nil {
+				return nil, err
+			}
+
+			svc := pushover.New(s.Token)
+			svc.AddReceivers(s.Recipients...)
+
+			return svc, nil
+		},
+		"mail": func(settings json.RawMessage) (notify.Notifier, error) {
+			s, err := decode[struct {
+				From       string   `json:"from"`
+				Host       string   `json:"host"`
+				Port       int      `json:"port"`
+				Username   string   `json:"username"`
+				Password   string   `json:"password"`
+				Recipients []string `json:"recipients"`
+				HTML       bool     `json:"html"`
+			}](settings)
+			if err != nil {
+				return nil, err
+			}
+
+			port := s.Port
+			if port == 0 {
+				port = 587
+			}
+
+			svc := mail.New(s.From, fmt.Sprintf("%s:%d", s.Host, port))
+			if s.Username != "" {
+				svc.AuthenticateSMTP("", s.Username, s.Password, s.Host)
+			}
+			if s.HTML {
+				svc.BodyFormat(mail.HTML)
+			}
+			svc.AddReceivers(s.Recipients...)
+
+			return svc, nil
+		},
+		"http": func(settings json.RawMessage) (notify.Notifier, error) {
+			s, err := decode[struct {
+				URLs []string `json:"urls"`
+			}](settings)
+			if err != nil {
+				return nil, err
+			}
+
+			svc := httpsvc.New()
+			svc.AddReceiversURLs(s.URLs...)
+
+			return svc, nil
+		},
+		"bark": func(settings json.RawMessage) (notify.Notifier, error) {
+			s, err := decode[struct {
+				DeviceKey string   `json:"device_key"`
+				Servers   []string `json:"servers"`
+			}](settings)
+			if err != nil {
+				return nil, err
+			}
+
+			return bark.NewWithServers(s.DeviceKey, s.Servers...), nil
+		},
+	}
+}
+
+func run() error {
+	configPath := flag.String("config", "notifyd.json", "path of the configuration file")
+	addr := flag.String("addr", ":8080", "address to listen on")
+	flag.Parse()
+
+	cfg, err := relay.LoadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	server, err := relay.NewServer(cfg, factories())
+	if err != nil {
+		return err
+	}
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           server,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      2 * time.Minute,
+		IdleTimeout:       2 * time.Minute,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errc := make(chan error, 1)
+	go func() {
+		log.Printf("notifyd listening on %s", *addr)
+		errc <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err = <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Print("shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	return httpServer.Shutdown(shutdownCtx)
+}
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
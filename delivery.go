@@ -0,0 +1,109 @@
+package notify
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// DeliveryOptions control how a service delivers a single message to its list of receivers.
+//
+// The zero value keeps the historical behavior: receivers are processed one after another and delivery stops at the
+// first failing receiver.
+type DeliveryOptions struct {
+	// ContinueOnError makes the service attempt delivery to every receiver, even if some of them fail. All failures are
+	// collected and returned as a single, joined error.
+	ContinueOnError bool
+
+	// Concurrency is the maximum number of receivers a message is delivered to in parallel. Values lower than 2 result
+	// in sequential delivery.
+	Concurrency int
+}
+
+// Deliver calls send for each of the given receivers, honoring the given DeliveryOptions. It is meant to be used by
+// notification services that fan out a single message to multiple receivers.
+//
+// Without ContinueOnError, the first error is returned as-is and no further receivers are attempted. With
+// ContinueOnError, the errors of all failed receivers are joined in receiver order. In both modes, a canceled context
+// stops delivery to the remaining receivers.
+func Deliver[R any](
+	ctx context.Context,
+	opts DeliveryOptions,
+	receivers []R,
+	send func(ctx context.Context, receiver R) error,
+) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if opts.Concurrency < 2 {
+		return deliverSequential(ctx, opts, receivers, send)
+	}
+
+	return deliverConcurrent(ctx, opts, receivers, send)
+}
+
+// deliverSequential delivers to one receiver after another.
+func deliverSequential[R any](
+	ctx context.Context,
+	opts DeliveryOptions,
+	receivers []R,
+	send func(ctx context.Context, receiver R) error,
+) error {
+	var errs []error
+	for _, receiver := range receivers {
+		select {
+		case <-ctx.Done():
+			return errors.Join(append(errs, ctx.Err())...)
+		default:
+		}
+
+		if err := send(ctx, receiver); err != nil {
+			if !opts.ContinueOnError {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// deliverConcurrent delivers to up to opts.Concurrency receivers at the same time.
+func deliverConcurrent[R any](
+	ctx context.Context,
+	opts DeliveryOptions,
+	receivers []R,
+	send func(ctx context.Context, receiver R) error,
+) error {
+	// Only cancel the remaining receivers on failure if we're not supposed to continue.
+	eg, egCtx := errgroup.WithContext(ctx)
+	if opts.ContinueOnError {
+		eg = &errgroup.Group{}
+		egCtx = ctx
+	}
+	eg.SetLimit(opts.Concurrency)
+
+	// Keep errors in receiver order, regardless of the order in which the sends finish.
+	errs := make([]error, len(receivers))
+	for i, receiver := range receivers {
+		eg.Go(func() error {
+			if err := egCtx.Err(); err != nil {
+				return err
+			}
+
+			errs[i] = send(egCtx, receiver)
+
+			return errs[i]
+		})
+	}
+
+	err := eg.Wait()
+	if !opts.ContinueOnError {
+		return err
+	}
+
+	// A canceled context is reported once, not once per skipped receiver.
+	return errors.Join(append(errs, ctx.Err())...)
+}
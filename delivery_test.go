@@ -0,0 +1,117 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliver(t *testing.T) {
+	t.Parallel()
+
+	receivers := []string{"a", "b", "c", "d"}
+	failing := map[string]bool{"b": true, "d": true}
+
+	tests := []struct {
+		name          string
+		opts          DeliveryOptions
+		wantAttempted []string
+		wantErrors    []string
+	}{
+		{
+			name:          "Sequential stops at first error",
+			opts:          DeliveryOptions{},
+			wantAttempted: []string{"a", "b"},
+			wantErrors:    []string{"b"},
+		},
+		{
+			name:          "Sequential continue on error",
+			opts:          DeliveryOptions{ContinueOnError: true},
+			wantAttempted: []string{"a", "b", "c", "d"},
+			wantErrors:    []string{"b", "d"},
+		},
+		{
+			name:          "Concurrent continue on error",
+			opts:          DeliveryOptions{ContinueOnError: true, Concurrency: 2},
+			wantAttempted: []string{"a", "b", "c", "d"},
+			wantErrors:    []string{"b", "d"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			var attempted []string
+
+			err := Deliver(context.Background(), tt.opts, receivers, func(_ context.Context, r string) error {
+				mu.Lock()
+				attempted = append(attempted, r)
+				mu.Unlock()
+
+				if failing[r] {
+					return fmt.Errorf("send to %s: failed", r)
+				}
+
+				return nil
+			})
+
+			require.ElementsMatch(t, tt.wantAttempted, attempted)
+			require.Error(t, err)
+
+			var want []error
+			for _, r := range tt.wantErrors {
+				want = append(want, fmt.Errorf("send to %s: failed", r))
+			}
+			require.EqualError(t, err, errors.Join(want...).Error())
+		})
+	}
+}
+
+func TestDeliver_ConcurrencyLimit(t *testing.T) {
+	t.Parallel()
+
+	var running, peak atomic.Int32
+	receivers := make([]int, 20)
+
+	err := Deliver(context.Background(), DeliveryOptions{Concurrency: 3}, receivers, func(context.Context, int) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.LessOrEqual(t, peak.Load(), int32(3))
+}
+
+func TestDeliver_CanceledContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, opts := range []DeliveryOptions{{}, {ContinueOnError: true, Concurrency: 4}} {
+		called := false
+		err := Deliver(ctx, opts, []string{"a", "b"}, func(context.Context, string) error {
+			called = true
+			return nil
+		})
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.False(t, called)
+	}
+}
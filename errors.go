@@ -0,0 +1,187 @@
+package notify
+
+import (
+	"errors"
+	"net/http"
+)
+
+// These are the error categories that services map their provider specific errors into. They can be matched using
+// errors.Is on any error returned by a service's Send method.
+var (
+	// ErrAuthFailure signals that the provider rejected the configured credentials.
+	ErrAuthFailure = errors.New("authentication failed")
+
+	// ErrInvalidReceiver signals that the receiver does not exist (anymore) or can't be reached by the service, e.g. a
+	// deleted channel or a bot that got blocked by the user.
+	ErrInvalidReceiver = errors.New("invalid receiver")
+
+	// ErrRateLimited signals that the provider throttled the request. These errors are usually retryable.
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrPayloadTooLarge signals that the provider rejected the message because of its size.
+	ErrPayloadTooLarge = errors.New("payload too large")
+)
+
+// ReceiverError describes the failed delivery of a message to a single receiver of a service.
+//
+// Its Error method returns the message of the wrapped cause, so that existing error messages stay unchanged. The
+// category (Kind) and the cause can be matched using errors.Is and errors.As.
+type ReceiverError struct {
+	// Service is the name of the service that failed, e.g. "slack".
+	Service string
+
+	// Receiver identifies the receiver the message was meant for, e.g. a channel ID or a webhook URL.
+	Receiver string
+
+	// StatusCode is the HTTP status code returned by the provider, if any.
+	StatusCode int
+
+	// Code is the provider specific error code, if any, e.g. "channel_not_found".
+	Code string
+
+	// Retryable reports whether sending the same message again might succeed.
+	Retryable bool
+
+	// Kind is one of the error categories defined by this package, e.g. ErrRateLimited. It's nil if the error could
+	// not be classified.
+	Kind error
+
+	// Err is the underlying cause.
+	Err error
+}
+
+// Error returns the message of the underlying cause.
+func (e *ReceiverError) Error() string {
+	if e.Err == nil {
+		if e.Kind != nil {
+			return e.Kind.Error()
+		}
+
+		return "send to " + e.Service + " receiver " + e.Receiver + " failed"
+	}
+
+	return e.Err.Error()
+}
+
+// Unwrap returns the error category and the underlying cause.
+func (e *ReceiverError) Unwrap() []error {
+	errs := make([]error, 0, 2)
+	if e.Kind != nil {
+		errs = append(errs, e.Kind)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+
+	return errs
+}
+
+// NewReceiverError returns a new ReceiverError for the given service and receiver, wrapping the given cause.
+//
+// If the cause carries an HTTP status code, exposed through a StatusCode() int or HTTPStatusCode() int method, it's used
+// to classify the error. A provider error code exposed through an ErrorCode() string method is copied to Code.
+func NewReceiverError(service, receiver string, err error) *ReceiverError {
+	e := &ReceiverError{
+		Service:  service,
+		Receiver: receiver,
+		Err:      err,
+	}
+
+	var sc interface{ StatusCode() int }
+	var hsc interface{ HTTPStatusCode() int }
+	switch {
+	case errors.As(err, &sc):
+		e.SetStatusCode(sc.StatusCode())
+	case errors.As(err, &hsc):
+		e.SetStatusCode(hsc.HTTPStatusCode())
+	}
+
+	var ec interface{ ErrorCode() string }
+	if errors.As(err, &ec) {
+		e.Code = ec.ErrorCode()
+	}
+
+	return e
+}
+
+// SetStatusCode sets the HTTP status code of the error and derives the error category and retryability from it, unless
+// they were already set.
+func (e *ReceiverError) SetStatusCode(statusCode int) *ReceiverError {
+	e.StatusCode = statusCode
+
+	kind, retryable := ClassifyStatusCode(statusCode)
+	if e.Kind == nil {
+		e.Kind = kind
+	}
+	e.Retryable = e.Retryable || retryable
+
+	return e
+}
+
+// SetKind sets the error category. Errors of kind ErrRateLimited are always marked as retryable.
+func (e *ReceiverError) SetKind(kind error) *ReceiverError {
+	e.Kind = kind
+	e.Retryable = e.Retryable || errors.Is(kind, ErrRateLimited)
+
+	return e
+}
+
+// ClassifyStatusCode maps an HTTP status code to one of the error categories of this package and reports whether a
+// request that failed with this status code is worth retrying.
+func ClassifyStatusCode(statusCode int) (kind error, retryable bool) {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrAuthFailure, false
+	case http.StatusNotFound, http.StatusGone:
+		return ErrInvalidReceiver, false
+	case http.StatusRequestEntityTooLarge:
+		return ErrPayloadTooLarge, false
+	case http.StatusTooManyRequests:
+		return ErrRateLimited, true
+	case http.StatusRequestTimeout:
+		return nil, true
+	}
+
+	return nil, statusCode >= http.StatusInternalServerError
+}
+
+// ReceiverErrors returns all ReceiverErrors contained in the given error tree. It's useful to inspect the result of a
+// send to multiple receivers, e.g. to remove receivers that failed with ErrInvalidReceiver.
+func ReceiverErrors(err error) []*ReceiverError {
+	var out []*ReceiverError
+
+	var walk func(error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+
+		if re, ok := err.(*ReceiverError); ok { //nolint:errorlint // We're walking the tree manually.
+			out = append(out, re)
+			return
+		}
+
+		switch x := err.(type) { //nolint:errorlint // We're walking the tree manually.
+		case interface{ Unwrap() []error }:
+			for _, e := range x.Unwrap() {
+				walk(e)
+			}
+		case interface{ Unwrap() error }:
+			walk(x.Unwrap())
+		}
+	}
+	walk(err)
+
+	return out
+}
+
+// IsRetryable reports whether any ReceiverError in the given error tree is retryable.
+func IsRetryable(err error) bool {
+	for _, re := range ReceiverErrors(err) {
+		if re.Retryable {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type statusCodeError int
+
+func (e statusCodeError) Error() string   { return fmt.Sprintf("status %d", int(e)) }
+func (e statusCodeError) StatusCode() int { return int(e) }
+
+func TestNewReceiverError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		cause         error
+		wantKind      error
+		wantStatus    int
+		wantRetryable bool
+	}{
+		{
+			name:  "Unclassified",
+			cause: errors.New("boom"),
+		},
+		{
+			name:       "Unauthorized",
+			cause:      statusCodeError(http.StatusUnauthorized),
+			wantKind:   ErrAuthFailure,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Gone",
+			cause:      fmt.Errorf("wrapped: %w", statusCodeError(http.StatusGone)),
+			wantKind:   ErrInvalidReceiver,
+			wantStatus: http.StatusGone,
+		},
+		{
+			name:          "Too many requests",
+			cause:         statusCodeError(http.StatusTooManyRequests),
+			wantKind:      ErrRateLimited,
+			wantStatus:    http.StatusTooManyRequests,
+			wantRetryable: true,
+		},
+		{
+			name:       "Payload too large",
+			cause:      statusCodeError(http.StatusRequestEntityTooLarge),
+			wantKind:   ErrPayloadTooLarge,
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:          "Server error",
+			cause:         statusCodeError(http.StatusBadGateway),
+			wantStatus:    http.StatusBadGateway,
+			wantRetryable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := NewReceiverError("test", "receiver", tt.cause)
+
+			require.Equal(t, "test", err.Service)
+			require.Equal(t, "receiver", err.Receiver)
+			require.Equal(t, tt.wantStatus, err.StatusCode)
+			require.Equal(t, tt.wantRetryable, err.Retryable)
+			require.Equal(t, tt.cause.Error(), err.Error())
+			require.ErrorIs(t, err, tt.cause)
+			if tt.wantKind != nil {
+				require.ErrorIs(t, err, tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestReceiverError_SetKind(t *testing.T) {
+	t.Parallel()
+
+	err := NewReceiverError("test", "receiver", errors.New("slow down")).SetKind(ErrRateLimited)
+
+	require.ErrorIs(t, err, ErrRateLimited)
+	require.True(t, err.Retryable)
+}
+
+func TestReceiverErrors(t *testing.T) {
+	t.Parallel()
+
+	first := NewReceiverError("test", "a", errors.New("a failed")).SetKind(ErrInvalidReceiver)
+	second := NewReceiverError("test", "b", errors.New("b failed")).SetKind(ErrRateLimited)
+
+	err := errors.Join(ErrSendNotification, fmt.Errorf("wrapped: %w", errors.Join(first, second)))
+
+	got := ReceiverErrors(err)
+	require.Equal(t, []*ReceiverError{first, second}, got)
+	require.True(t, IsRetryable(err))
+	require.False(t, IsRetryable(first))
+	require.Empty(t, ReceiverErrors(errors.New("plain")))
+}
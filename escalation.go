@@ -0,0 +1,354 @@
+package notify
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrIncidentNotFound signals that no open incident with the given ID exists, e.g. because it was already acknowledged.
+var ErrIncidentNotFound = errors.New("incident not found")
+
+// EscalationTier is a single step of an EscalationPolicy.
+type EscalationTier struct {
+	// Notifier receives the incident when it's escalated to this tier.
+	Notifier Notifier
+
+	// Timeout is how long the tier has to acknowledge the incident before it's escalated to the next tier. For the last
+	// tier, it's how long the incident can still be acknowledged before it's closed; without a timeout, the incident is
+	// closed right after the last tier was notified.
+	Timeout time.Duration
+}
+
+// EscalationOptions configure an EscalationPolicy.
+type EscalationOptions struct {
+	// Tiers are the escalation steps, in order. An incident is sent to the first tier right away.
+	Tiers []EscalationTier
+
+	// AckURL, if set, is the URL at which the handler returned by EscalationPolicy.Handler is served. Every message
+	// gets a link appended that allows its receivers to acknowledge the incident.
+	AckURL string
+
+	// OnEscalate, if set, is called whenever an incident is escalated to the given tier, starting at 1.
+	OnEscalate func(incident Incident, tier int)
+
+	// OnAcknowledge, if set, is called whenever an incident gets acknowledged.
+	OnAcknowledge func(incident Incident, by string)
+
+	// OnError, if set, is called with the error of a tier that failed to receive an incident. Failed tiers are
+	// skipped, i.e. the incident is escalated to the next tier right away.
+	OnError func(incident Incident, tier int, err error)
+}
+
+// Incident is a notification that is escalated until somebody acknowledges it.
+type Incident struct {
+	// ID uniquely identifies the incident.
+	ID string
+
+	// Subject is the subject of the notification.
+	Subject string
+
+	// Message is the body of the notification, without the acknowledgement link.
+	Message string
+
+	// Tier is the index of the tier the incident was last sent to.
+	Tier int
+
+	// TriggeredAt is the time the incident was triggered at.
+	TriggeredAt time.Time
+}
+
+// incident is an open incident together with its state.
+type incident struct {
+	Incident
+
+	token string
+	stop  func() bool // Stops the pending escalation, if any.
+}
+
+// EscalationPolicy sends notifications to a sequence of tiers, e.g. Slack first, then Telegram, and finally SMS, until
+// somebody acknowledges them. Acknowledgements are either reported by calling Acknowledge, or by following the link
+// that is appended to every message if an AckURL is configured.
+//
+// Open incidents are kept in memory until they get acknowledged, or until their escalation ends: once the timeout of
+// the last tier elapses, or once all remaining tiers failed to receive them. An EscalationPolicy is safe for concurrent
+// use.
+type EscalationPolicy struct {
+	opts      EscalationOptions
+	now       func() time.Time
+	afterFunc func(d time.Duration, f func()) (stop func() bool)
+
+	mu        sync.Mutex
+	incidents map[string]*incident
+}
+
+// Compile-time check to ensure EscalationPolicy implements Notifier.
+var _ Notifier = (*EscalationPolicy)(nil)
+
+// NewEscalationPolicy returns a new EscalationPolicy with the given options.
+func NewEscalationPolicy(opts EscalationOptions) *EscalationPolicy {
+	return &EscalationPolicy{
+		opts: opts,
+		now:  time.Now,
+		afterFunc: func(d time.Duration, f func()) func() bool {
+			return time.AfterFunc(d, f).Stop
+		},
+		incidents: make(map[string]*incident),
+	}
+}
+
+// Send triggers a new incident. It implements the Notifier interface, which allows using an EscalationPolicy as a
+// service of a Notify instance. Use Trigger to get the ID of the incident.
+func (p *EscalationPolicy) Send(ctx context.Context, subject, message string) error {
+	_, err := p.Trigger(ctx, subject, message)
+
+	return err
+}
+
+// Trigger creates a new incident, sends it to the first tier and returns its ID. The returned error is the error of the
+// first tier, if any; the incident is escalated regardless. The values of the given context, e.g. the severity, are
+// passed on to all tiers, while its cancellation only applies to the first one.
+func (p *EscalationPolicy) Trigger(ctx context.Context, subject, message string) (string, error) {
+	if len(p.opts.Tiers) == 0 {
+		return "", errors.New("escalation policy has no tiers")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+	token, err := newID()
+	if err != nil {
+		return "", err
+	}
+
+	inc := &incident{
+		Incident: Incident{
+			ID:          id,
+			Subject:     subject,
+			Message:     message,
+			TriggeredAt: p.now(),
+		},
+		token: token,
+	}
+
+	p.mu.Lock()
+	p.incidents[id] = inc
+	p.mu.Unlock()
+
+	return id, p.escalate(ctx, id, 0)
+}
+
+// escalate sends the incident with the given ID to the given tier and arms the timer for the next one. Tiers that fail
+// are skipped; the incident is closed if all remaining tiers failed. It returns the error of the given tier, if any.
+func (p *EscalationPolicy) escalate(ctx context.Context, id string, tier int) error {
+	var firstErr error
+
+	for ; tier < len(p.opts.Tiers); tier++ {
+		p.mu.Lock()
+		inc, ok := p.incidents[id]
+		if !ok {
+			p.mu.Unlock()
+			return firstErr // Acknowledged in the meantime.
+		}
+		inc.Tier = tier
+		snapshot := inc.Incident
+		message := p.messageWithLink(inc)
+		p.mu.Unlock()
+
+		if tier > 0 && p.opts.OnEscalate != nil {
+			p.opts.OnEscalate(snapshot, tier)
+		}
+
+		err := p.opts.Tiers[tier].Notifier.Send(ctx, snapshot.Subject, message)
+
+		// The cancellation of the context only applies to the first tier that is tried.
+		ctx = context.WithoutCancel(ctx)
+
+		if err == nil {
+			p.armTimer(ctx, id, tier)
+			return firstErr
+		}
+
+		err = fmt.Errorf("escalation tier %d: %w", tier, err)
+		if firstErr == nil {
+			firstErr = err
+		}
+		if p.opts.OnError != nil {
+			p.opts.OnError(snapshot, tier, err)
+		}
+	}
+
+	p.close(id)
+
+	return firstErr
+}
+
+// armTimer schedules the escalation of the incident with the given ID to the tier after the given one, or closes the
+// incident once the timeout of the last tier elapses.
+func (p *EscalationPolicy) armTimer(ctx context.Context, id string, tier int) {
+	timeout := p.opts.Tiers[tier].Timeout
+
+	next := func() { p.close(id) }
+	if tier+1 < len(p.opts.Tiers) {
+		ctx = context.WithoutCancel(ctx)
+		next = func() {
+			_ = p.escalate(ctx, id, tier+1) // Errors are reported through OnError.
+		}
+	} else if timeout <= 0 {
+		p.close(id)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if inc, ok := p.incidents[id]; ok {
+		inc.stop = p.afterFunc(timeout, next)
+	}
+}
+
+// close removes the incident with the given ID without acknowledging it.
+func (p *EscalationPolicy) close(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.incidents, id)
+}
+
+// messageWithLink appends the acknowledgement link to the message of the given incident. The caller must hold the lock.
+func (p *EscalationPolicy) messageWithLink(inc *incident) string {
+	if p.opts.AckURL == "" {
+		return inc.Message
+	}
+
+	return inc.Message + "\n\nAcknowledge: " + p.ackLink(inc)
+}
+
+// ackLink returns the acknowledgement link of the given incident.
+func (p *EscalationPolicy) ackLink(inc *incident) string {
+	query := url.Values{"id": {inc.ID}, "token": {inc.token}}
+
+	return p.opts.AckURL + "?" + query.Encode()
+}
+
+// Acknowledge acknowledges the incident with the given ID, which stops its escalation. The given name of whoever
+// acknowledged the incident is passed to OnAcknowledge. It returns ErrIncidentNotFound if the incident doesn't exist
+// or was already acknowledged.
+func (p *EscalationPolicy) Acknowledge(id, by string) error {
+	p.mu.Lock()
+	inc, ok := p.incidents[id]
+	if ok {
+		if inc.stop != nil {
+			inc.stop()
+		}
+		delete(p.incidents, id)
+	}
+	p.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrIncidentNotFound, id)
+	}
+
+	if p.opts.OnAcknowledge != nil {
+		p.opts.OnAcknowledge(inc.Incident, by)
+	}
+
+	return nil
+}
+
+// Incidents returns all open incidents, ordered by the time they were triggered at.
+func (p *EscalationPolicy) Incidents() []Incident {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	incidents := make([]Incident, 0, len(p.incidents))
+	for _, inc := range p.incidents {
+		incidents = append(incidents, inc.Incident)
+	}
+	slices.SortFunc(incidents, func(a, b Incident) int {
+		return a.TriggeredAt.Compare(b.TriggeredAt)
+	})
+
+	return incidents
+}
+
+// verifyToken reports whether the given token belongs to the open incident with the given ID.
+func (p *EscalationPolicy) verifyToken(id, token string) (Incident, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	inc, ok := p.incidents[id]
+	if !ok || subtle.ConstantTimeCompare([]byte(inc.token), []byte(token)) != 1 {
+		return Incident{}, false
+	}
+
+	return inc.Incident, true
+}
+
+// ackPage is the page served by the acknowledgement handler. GET requests only render a confirmation button, so that
+// link previews of chat apps don't acknowledge incidents by accident.
+var ackPage = template.Must(template.New("ack").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Incident.Subject}}</title></head>
+<body>
+<h1>{{.Incident.Subject}}</h1>
+{{if .Acknowledged}}<p>The incident has been acknowledged.</p>{{else}}
+<form method="post">
+<input type="hidden" name="id" value="{{.Incident.ID}}">
+<input type="hidden" name="token" value="{{.Token}}">
+<label>Name <input type="text" name="by"></label>
+<button type="submit">Acknowledge</button>
+</form>{{end}}
+</body>
+</html>
+`))
+
+// Handler returns an http.Handler that acknowledges incidents through the links appended to the messages. It should be
+// served at the configured AckURL. GET requests show a confirmation form, POST requests acknowledge the incident.
+func (p *EscalationPolicy) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		id, token := r.FormValue("id"), r.FormValue("token")
+		inc, ok := p.verifyToken(id, token)
+		if !ok {
+			http.Error(w, "incident not found or already acknowledged", http.StatusNotFound)
+			return
+		}
+
+		acknowledged := false
+		if r.Method == http.MethodPost {
+			by := r.FormValue("by")
+			if by == "" {
+				by = "link"
+			}
+			if err := p.Acknowledge(id, by); err != nil {
+				http.Error(w, "incident not found or already acknowledged", http.StatusNotFound)
+				return
+			}
+			acknowledged = true
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = ackPage.Execute(w, map[string]any{
+			"Incident":     inc,
+			"Token":        token,
+			"Acknowledged": acknowledged,
+		})
+	})
+}
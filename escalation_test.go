@@ -0,0 +1,340 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// messageNotifier is a Notifier that passes the body of every message it's sent to a channel.
+type messageNotifier chan string
+
+func (m messageNotifier) Send(_ context.Context, _, message string) error {
+	m <- message
+	return nil
+}
+
+// receive waits for a message on the given channel and fails the test if none arrives.
+func receive(t *testing.T, ch messageNotifier) string {
+	t.Helper()
+
+	select {
+	case msg := <-ch:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("no message received")
+		return ""
+	}
+}
+
+// fakeClock is a clock that is advanced manually, which allows testing timeouts without waiting.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// fakeTimer is a timer of a fakeClock.
+type fakeTimer struct {
+	at   time.Time
+	f    func()
+	done bool // Whether the timer fired or was stopped.
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) func() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	timer := &fakeTimer{at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, timer)
+
+	return func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		stopped := !timer.done
+		timer.done = true
+
+		return stopped
+	}
+}
+
+// Advance moves the clock forward by the given duration and runs the functions of the timers that are due, in order.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+
+	var due []*fakeTimer
+	c.timers = slices.DeleteFunc(c.timers, func(timer *fakeTimer) bool {
+		if !timer.done && !timer.at.After(c.now) {
+			timer.done = true
+			due = append(due, timer)
+		}
+
+		return timer.done
+	})
+	c.mu.Unlock()
+
+	slices.SortStableFunc(due, func(a, b *fakeTimer) int { return a.at.Compare(b.at) })
+	for _, timer := range due {
+		timer.f()
+	}
+}
+
+// withClock makes the given policy use the given clock.
+func withClock(policy *EscalationPolicy, clock *fakeClock) *EscalationPolicy {
+	policy.now = clock.Now
+	policy.afterFunc = clock.AfterFunc
+
+	return policy
+}
+
+func TestEscalationPolicy(t *testing.T) {
+	t.Parallel()
+
+	tier1, tier2, tier3 := make(messageNotifier, 1), make(messageNotifier, 1), make(messageNotifier, 1)
+	escalated := make(chan int, 3)
+	acknowledged := make(chan string, 1)
+
+	clock := newFakeClock()
+	policy := withClock(NewEscalationPolicy(EscalationOptions{
+		Tiers: []EscalationTier{
+			{Notifier: tier1, Timeout: 10 * time.Minute},
+			{Notifier: tier2, Timeout: time.Hour},
+			{Notifier: tier3},
+		},
+		OnEscalate:    func(_ Incident, tier int) { escalated <- tier },
+		OnAcknowledge: func(_ Incident, by string) { acknowledged <- by },
+	}), clock)
+
+	id, err := policy.Trigger(context.Background(), "disk full", "message")
+	require.NoError(t, err)
+	require.Equal(t, "message", receive(t, tier1))
+	require.Equal(t, clock.Now(), policy.Incidents()[0].TriggeredAt)
+
+	clock.Advance(9 * time.Minute)
+	require.Empty(t, tier2)
+
+	// Nobody acknowledged in time, so the second tier gets notified.
+	clock.Advance(time.Minute)
+	require.Equal(t, "message", receive(t, tier2))
+	require.Equal(t, 1, <-escalated)
+	require.Len(t, policy.Incidents(), 1)
+	require.Equal(t, 1, policy.Incidents()[0].Tier)
+
+	require.NoError(t, policy.Acknowledge(id, "alice"))
+	require.Equal(t, "alice", <-acknowledged)
+	require.Empty(t, policy.Incidents())
+	require.ErrorIs(t, policy.Acknowledge(id, "bob"), ErrIncidentNotFound)
+
+	clock.Advance(time.Hour)
+	require.Empty(t, tier3)
+}
+
+func TestEscalationPolicy_AcknowledgeStopsEscalation(t *testing.T) {
+	t.Parallel()
+
+	tier1, tier2 := make(messageNotifier, 1), make(messageNotifier, 1)
+	clock := newFakeClock()
+	policy := withClock(NewEscalationPolicy(EscalationOptions{
+		Tiers: []EscalationTier{
+			{Notifier: tier1, Timeout: time.Minute},
+			{Notifier: tier2},
+		},
+	}), clock)
+
+	id, err := policy.Trigger(context.Background(), "subject", "message")
+	require.NoError(t, err)
+	receive(t, tier1)
+	require.NoError(t, policy.Acknowledge(id, "alice"))
+
+	clock.Advance(time.Hour)
+	require.Empty(t, tier2)
+}
+
+func TestEscalationPolicy_Close(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Last tier notified", func(t *testing.T) {
+		t.Parallel()
+
+		tier1, tier2 := make(messageNotifier, 1), make(messageNotifier, 1)
+		clock := newFakeClock()
+		policy := withClock(NewEscalationPolicy(EscalationOptions{
+			Tiers: []EscalationTier{
+				{Notifier: tier1, Timeout: time.Minute},
+				{Notifier: tier2, Timeout: time.Hour},
+			},
+		}), clock)
+
+		id, err := policy.Trigger(context.Background(), "subject", "message")
+		require.NoError(t, err)
+		receive(t, tier1)
+
+		clock.Advance(time.Minute)
+		receive(t, tier2)
+		require.Len(t, policy.Incidents(), 1, "the last tier must be able to acknowledge the incident")
+
+		clock.Advance(time.Hour)
+		require.Empty(t, policy.Incidents())
+		require.ErrorIs(t, policy.Acknowledge(id, "alice"), ErrIncidentNotFound)
+	})
+
+	t.Run("Last tier without timeout", func(t *testing.T) {
+		t.Parallel()
+
+		tier1 := make(messageNotifier, 1)
+		policy := NewEscalationPolicy(EscalationOptions{
+			Tiers: []EscalationTier{{Notifier: tier1}},
+		})
+
+		_, err := policy.Trigger(context.Background(), "subject", "message")
+		require.NoError(t, err)
+		receive(t, tier1)
+		require.Empty(t, policy.Incidents())
+	})
+
+	t.Run("All tiers failed", func(t *testing.T) {
+		t.Parallel()
+
+		tier1 := make(messageNotifier, 1)
+		clock := newFakeClock()
+		policy := withClock(NewEscalationPolicy(EscalationOptions{
+			Tiers: []EscalationTier{
+				{Notifier: tier1, Timeout: time.Minute},
+				{Notifier: newFailingNotifier(), Timeout: time.Minute},
+				{Notifier: newFailingNotifier(), Timeout: time.Hour},
+			},
+		}), clock)
+
+		_, err := policy.Trigger(context.Background(), "subject", "message")
+		require.NoError(t, err)
+		receive(t, tier1)
+		require.Len(t, policy.Incidents(), 1)
+
+		clock.Advance(time.Minute)
+		require.Empty(t, policy.Incidents())
+	})
+}
+
+func TestEscalationPolicy_FailingTier(t *testing.T) {
+	t.Parallel()
+
+	tier2 := make(messageNotifier, 1)
+	failed := make(chan int, 1)
+
+	policy := NewEscalationPolicy(EscalationOptions{
+		Tiers: []EscalationTier{
+			{Notifier: newFailingNotifier(), Timeout: time.Hour},
+			{Notifier: tier2},
+		},
+		OnError: func(_ Incident, tier int, _ error) { failed <- tier },
+	})
+
+	_, err := policy.Trigger(context.Background(), "subject", "message")
+	require.ErrorContains(t, err, "escalation tier 0: send failed")
+	require.Equal(t, 0, <-failed)
+	require.Equal(t, "message", receive(t, tier2))
+
+	_, err = NewEscalationPolicy(EscalationOptions{}).Trigger(context.Background(), "subject", "message")
+	require.Error(t, err)
+}
+
+// uncanceledNotifier is a Notifier that fails if the context is done and passes the message on otherwise.
+type uncanceledNotifier messageNotifier
+
+func (u uncanceledNotifier) Send(ctx context.Context, subject, message string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return messageNotifier(u).Send(ctx, subject, message)
+}
+
+func TestEscalationPolicy_CanceledContext(t *testing.T) {
+	t.Parallel()
+
+	tier2 := make(messageNotifier, 1)
+	policy := NewEscalationPolicy(EscalationOptions{
+		Tiers: []EscalationTier{
+			{Notifier: contextNotifier{}, Timeout: time.Hour},
+			{Notifier: uncanceledNotifier(tier2), Timeout: time.Hour},
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Only the first tier is affected by the cancellation.
+	_, err := policy.Trigger(ctx, "subject", "message")
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, "message", receive(t, tier2))
+	require.Len(t, policy.Incidents(), 1)
+}
+
+func TestEscalationPolicy_Handler(t *testing.T) {
+	t.Parallel()
+
+	tier1 := make(messageNotifier, 1)
+	policy := NewEscalationPolicy(EscalationOptions{
+		Tiers:  []EscalationTier{{Notifier: tier1, Timeout: time.Hour}},
+		AckURL: "https://example.com/ack",
+	})
+
+	_, err := policy.Trigger(context.Background(), "subject", "message")
+	require.NoError(t, err)
+
+	message := receive(t, tier1)
+	_, link, ok := strings.Cut(message, "\n\nAcknowledge: ")
+	require.True(t, ok, "message has no acknowledgement link: %s", message)
+
+	ackURL, err := url.Parse(link)
+	require.NoError(t, err)
+	query := ackURL.Query()
+
+	handler := policy.Handler()
+
+	// Opening the link only shows the confirmation form.
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ack?"+ackURL.RawQuery, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "<form")
+	require.Len(t, policy.Incidents(), 1)
+
+	// A wrong token is rejected.
+	wrong := url.Values{"id": {query.Get("id")}, "token": {"wrong"}}
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/ack", strings.NewReader(wrong.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	// Submitting the form acknowledges the incident.
+	query.Set("by", "alice")
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/ack", strings.NewReader(query.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "has been acknowledged")
+	require.Empty(t, policy.Incidents())
+}
@@ -42,6 +42,8 @@ require (
 	github.com/jordan-wright/email v4.0.1-0.20210109023952-943e75fe5223+incompatible
 	github.com/mailgun/mailgun-go/v5 v5.19.1
 	github.com/textmagic/textmagic-rest-go-v2/v3 v3.0.50044
+	golang.org/x/net v0.58.0
+	golang.org/x/oauth2 v0.36.0
 	google.golang.org/api v0.293.0
 )
 
@@ -130,7 +132,7 @@ require (
 	github.com/gorilla/websocket v1.5.3 // indirect
 	github.com/gregdel/pushover v1.4.0
 	github.com/kevinburke/go-types v0.0.0-20240719050749-165e75e768f7 // indirect
-	github.com/kevinburke/rest v0.0.0-20250718180114-1a15e4f2364f // indirect
+	github.com/kevinburke/rest v0.0.0-20250718180114-1a15e4f2364f
 	github.com/mileusna/viber v1.0.1
 	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
 	github.com/sendgrid/rest v2.6.9+incompatible // indirect
@@ -145,8 +147,6 @@ require (
 	github.com/ttacon/builder v0.0.0-20170518171403-c099f663e1c2 // indirect
 	github.com/ttacon/libphonenumber v1.2.1 // indirect
 	golang.org/x/crypto v0.55.0 // indirect
-	golang.org/x/net v0.58.0 // indirect
-	golang.org/x/oauth2 v0.36.0 // indirect
 	golang.org/x/sys v0.47.0 // indirect
 	golang.org/x/text v0.41.0 // indirect
 	google.golang.org/protobuf v1.36.12 // indirect
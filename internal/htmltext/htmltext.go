@@ -0,0 +1,202 @@
+// Package htmltext converts HTML messages into plain text, e.g. for the plain-text part of multipart/alternative mails.
+package htmltext
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Convert returns a readable plain-text version of the given HTML. Whitespace is collapsed, block elements start new
+// lines or paragraphs, list items are prefixed with "- ", and links are followed by their target in parentheses unless
+// the link text is the target itself. Scripts, styles, and the document head are dropped; invalid HTML is converted on
+// a best-effort basis.
+func Convert(s string) string {
+	var (
+		w         writer
+		skipDepth int
+		preDepth  int
+		links     []link
+	)
+
+	tokenizer := html.NewTokenizer(strings.NewReader(s))
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			return strings.TrimSpace(w.String())
+		case html.TextToken:
+			if skipDepth > 0 {
+				continue
+			}
+			if preDepth > 0 {
+				w.writeRaw(string(tokenizer.Text()))
+			} else {
+				w.writeText(string(tokenizer.Text()))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			attrs := attributes(tokenizer, hasAttr)
+
+			switch atom.Lookup(name) {
+			case atom.Script, atom.Style, atom.Head, atom.Title:
+				if tokenType == html.StartTagToken {
+					skipDepth++
+				}
+			case atom.Br:
+				w.newline()
+			case atom.Hr:
+				w.paragraph()
+				w.writeRaw("----")
+				w.paragraph()
+			case atom.Li:
+				w.newline()
+				w.writeRaw("- ")
+			case atom.Tr, atom.Dt, atom.Dd:
+				w.newline()
+			case atom.Td, atom.Th:
+				w.space = true
+			case atom.P, atom.Div, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Table, atom.Ul, atom.Ol,
+				atom.Dl, atom.Blockquote, atom.Section, atom.Article, atom.Header, atom.Footer:
+				w.paragraph()
+			case atom.Pre:
+				w.paragraph()
+				preDepth++
+			case atom.A:
+				if tokenType == html.StartTagToken {
+					links = append(links, link{href: attrs["href"], start: w.Len()})
+				}
+			case atom.Img:
+				w.writeText(attrs["alt"])
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+
+			switch atom.Lookup(name) {
+			case atom.Script, atom.Style, atom.Head, atom.Title:
+				skipDepth = max(skipDepth-1, 0)
+			case atom.Li, atom.Tr, atom.Dt, atom.Dd:
+				w.newline()
+			case atom.P, atom.Div, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Table, atom.Ul, atom.Ol,
+				atom.Dl, atom.Blockquote, atom.Section, atom.Article, atom.Header, atom.Footer:
+				w.paragraph()
+			case atom.Pre:
+				preDepth = max(preDepth-1, 0)
+				w.paragraph()
+			case atom.A:
+				if len(links) == 0 {
+					continue
+				}
+				l := links[len(links)-1]
+				links = links[:len(links)-1]
+				w.writeLinkTarget(l)
+			}
+		}
+	}
+}
+
+// link is an anchor whose end tag hasn't been reached yet.
+type link struct {
+	href  string
+	start int // Length of the output when the anchor was opened.
+}
+
+// attributes returns the attributes of the current tag of the given tokenizer.
+func attributes(tokenizer *html.Tokenizer, hasAttr bool) map[string]string {
+	attrs := make(map[string]string)
+	for hasAttr {
+		var key, value []byte
+		key, value, hasAttr = tokenizer.TagAttr()
+		attrs[string(key)] = string(value)
+	}
+
+	return attrs
+}
+
+// writer builds the plain text. It collapses whitespace and keeps track of the line breaks at the end of the output, so
+// that consecutive block elements don't produce more than one empty line.
+type writer struct {
+	strings.Builder
+	newlines int  // Number of line breaks at the end of the output.
+	space    bool // Whether a space is pending before the next word.
+}
+
+// writeText writes the given text with collapsed whitespace.
+func (w *writer) writeText(text string) {
+	if text == "" {
+		return
+	}
+
+	if unicode.IsSpace(rune(text[0])) {
+		w.space = true
+	}
+
+	for i, word := range strings.Fields(text) {
+		if i > 0 {
+			w.space = true
+		}
+		w.writeRaw(word)
+	}
+
+	if unicode.IsSpace(rune(text[len(text)-1])) {
+		w.space = true
+	}
+}
+
+// writeRaw writes the given text as is, preceded by a pending space, if any.
+func (w *writer) writeRaw(text string) {
+	if text == "" {
+		return
+	}
+
+	if w.space && w.Len() > 0 && w.newlines == 0 {
+		w.WriteByte(' ')
+	}
+	w.space = false
+
+	w.WriteString(text)
+	w.newlines = len(text) - len(strings.TrimRight(text, "\n"))
+}
+
+// writeLinkTarget writes the target of the given link after its text, unless the text is the target itself.
+func (w *writer) writeLinkTarget(l link) {
+	target := l.href
+	if target == "" || strings.HasPrefix(target, "#") {
+		return
+	}
+
+	text := strings.TrimSpace(w.String()[l.start:])
+	if text == target || "mailto:"+text == target {
+		return
+	}
+
+	if text == "" {
+		w.writeText(target)
+	} else {
+		w.writeText(" (" + target + ")")
+	}
+}
+
+// newline ends the current line.
+func (w *writer) newline() {
+	w.breakLines(1)
+}
+
+// paragraph ends the current paragraph, leaving an empty line.
+func (w *writer) paragraph() {
+	w.breakLines(2)
+}
+
+// breakLines makes the output end with at least the given number of line breaks, unless it's empty.
+func (w *writer) breakLines(n int) {
+	w.space = false
+	if w.Len() == 0 {
+		return
+	}
+
+	for ; w.newlines < n; w.newlines++ {
+		w.WriteByte('\n')
+	}
+}
@@ -0,0 +1,68 @@
+package htmltext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "Plain text",
+			html: "Disk   full\n on  db-1",
+			want: "Disk full on db-1",
+		},
+		{
+			name: "Paragraphs and line breaks",
+			html: "<h1>Backup failed</h1><p>Job <b>nightly</b> failed.<br>Retrying in 5&nbsp;minutes.</p><p>Bye</p>",
+			want: "Backup failed\n\nJob nightly failed.\nRetrying in 5 minutes.\n\nBye",
+		},
+		{
+			name: "Lists",
+			html: "<p>Failed jobs:</p><ul><li>backup</li><li>cleanup</li></ul>",
+			want: "Failed jobs:\n\n- backup\n- cleanup",
+		},
+		{
+			name: "Links",
+			html: `See <a href="https://ci.example.com/42">the log</a> or <a href="https://example.com">https://example.com</a>` +
+				` or <a href="mailto:ops@example.com">ops@example.com</a> or <a href="#top">top</a>.`,
+			want: "See the log (https://ci.example.com/42) or https://example.com or ops@example.com or top.",
+		},
+		{
+			name: "Scripts, styles and head are dropped",
+			html: "<html><head><title>Alert</title><style>p { color: red; }</style></head>" +
+				"<body><script>alert(1)</script><p>Visible</p></body></html>",
+			want: "Visible",
+		},
+		{
+			name: "Preformatted text",
+			html: "<p>Output:</p><pre>line 1\n  line 2</pre>",
+			want: "Output:\n\nline 1\n  line 2",
+		},
+		{
+			name: "Tables",
+			html: "<table><tr><th>Host</th><th>Load</th></tr><tr><td>db-1</td><td>12.5</td></tr></table>",
+			want: "Host Load\ndb-1 12.5",
+		},
+		{
+			name: "Images",
+			html: `<p><img src="logo.png" alt="ACME"> Status</p>`,
+			want: "ACME Status",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, Convert(tt.html))
+		})
+	}
+}
@@ -3,6 +3,7 @@ package notify
 import (
 	context "context"
 	"errors"
+	"sync"
 )
 
 // ErrSendNotification signals that the notifier failed to send a notification.
@@ -20,10 +21,16 @@ type Notifier interface {
 // Compile-time check to ensure Notify implements Notifier.
 var _ Notifier = (*Notify)(nil)
 
-// Notify is the central struct for managing notification services and sending messages to them.
+// Notify is the central struct for managing notification services and sending messages to them. Its methods for
+// managing services are safe for concurrent use, also while messages are being sent.
 type Notify struct {
-	Disabled  bool
-	notifiers []Notifier
+	Disabled bool
+
+	mu       sync.RWMutex
+	services []*serviceEntry
+
+	schedulerOnce sync.Once
+	sched         *Scheduler
 }
 
 // Option is a function that can be used to configure a Notify instance. It is used by the WithOptions and
@@ -65,8 +72,8 @@ func (n *Notify) WithOptions(options ...Option) *Notify {
 // Notify instance with default options. By default, the Notify instance is enabled.
 func NewWithOptions(options ...Option) *Notify {
 	n := &Notify{
-		Disabled:  false,               // Enabled by default.
-		notifiers: make([]Notifier, 0), // Avoid nil list.
+		Disabled: false,                    // Enabled by default.
+		services: make([]*serviceEntry, 0), // Avoid nil list.
 	}
 
 	return n.WithOptions(options...)
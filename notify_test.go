@@ -2,13 +2,26 @@ package notify
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-
-	"github.com/nikoksr/notify/service/mail"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
+// failingNotifier is a Notifier that fails on every send.
+type failingNotifier struct {
+	reason string
+}
+
+func newFailingNotifier() *failingNotifier {
+	return &failingNotifier{reason: "send failed"}
+}
+
+func (f *failingNotifier) Send(context.Context, string, string) error {
+	return errors.New(f.reason)
+}
+
 func TestNew(t *testing.T) {
 	t.Parallel()
 
@@ -22,7 +35,7 @@ func TestNew(t *testing.T) {
 	if n2 == nil {
 		t.Fatal("NewWithOptions() returned nil")
 	}
-	diff := cmp.Diff(n1, n2, cmp.AllowUnexported(Notify{}))
+	diff := cmp.Diff(n1, n2, cmp.AllowUnexported(Notify{}), cmpopts.IgnoreFields(Notify{}, "mu", "schedulerOnce"))
 	if diff != "" {
 		t.Errorf("New() and NewWithOptions() returned different Notifiers:\n%s", diff)
 	}
@@ -37,11 +50,14 @@ func TestNew(t *testing.T) {
 		t.Error("WithOptions(Enable) did not enable Notifier")
 	}
 
-	n3Copy := *n3
+	disabled, services := n3.Disabled, n3.Services()
 	n3.WithOptions()
-	diff = cmp.Diff(n3, &n3Copy, cmp.AllowUnexported(Notify{}))
+	if n3.Disabled != disabled {
+		t.Error("WithOptions() altered the Notifier's state")
+	}
+	diff = cmp.Diff(services, n3.Services())
 	if diff != "" {
-		t.Errorf("WithOptions() altered the Notifier:\n%s", diff)
+		t.Errorf("WithOptions() altered the Notifier's services:\n%s", diff)
 	}
 
 	n3.WithOptions(nil)
@@ -69,18 +85,18 @@ func TestNewWithServices(t *testing.T) {
 
 	n2 := NewWithServices(nil)
 
-	if len(n2.notifiers) != 0 {
+	if len(n2.services) != 0 {
 		t.Error("NewWithServices(nil) did not return empty Notifier")
 	}
 
-	mailService := mail.New("", "")
-	n3 := NewWithServices(mailService)
-	if len(n3.notifiers) != 1 {
-		t.Errorf("NewWithServices(mail.New()) was expected to have 1 notifier but had %d", len(n3.notifiers))
+	service := newFailingNotifier()
+	n3 := NewWithServices(service)
+	if len(n3.services) != 1 {
+		t.Errorf("NewWithServices(newFailingNotifier()) was expected to have 1 notifier but had %d", len(n3.services))
 	} else {
-		diff := cmp.Diff(n3.notifiers[0], mailService, cmp.AllowUnexported(mail.Mail{}))
+		diff := cmp.Diff(n3.services[0].service, service, cmp.AllowUnexported(failingNotifier{}))
 		if diff != "" {
-			t.Errorf("NewWithServices(mail.New()) did not correctly use service:\n%s", diff)
+			t.Errorf("NewWithServices(newFailingNotifier()) did not correctly use service:\n%s", diff)
 		}
 	}
 }
@@ -93,12 +109,27 @@ func TestGlobal(t *testing.T) {
 		t.Errorf("Send() with no receivers returned error: %v", err)
 	}
 
-	UseServices(mail.New("", ""), nil)
-	if len(std.notifiers) != 1 {
-		t.Errorf("UseServices(mail.New()) was expected to have 1 notifier but had %d", len(std.notifiers))
+	UseServices(newFailingNotifier(), nil)
+	if len(std.services) != 1 {
+		t.Errorf("UseServices(newFailingNotifier()) was expected to have 1 notifier but had %d", len(std.services))
 	}
 
 	if err := Send(ctx, "subject", "message"); err == nil {
-		t.Error("Send() with invalid mail returned no error")
+		t.Error("Send() with failing service returned no error")
+	}
+
+	name := Services()[0].Name
+	if err := DisableService(name); err != nil {
+		t.Fatalf("DisableService(%q) returned error: %v", name, err)
+	}
+	if err := Send(ctx, "subject", "message"); err != nil {
+		t.Errorf("Send() with disabled service returned error: %v", err)
+	}
+
+	if err := EnableService(name); err != nil {
+		t.Fatalf("EnableService(%q) returned error: %v", name, err)
+	}
+	if err := Send(ctx, "subject", "message"); err == nil {
+		t.Error("Send() with enabled failing service returned no error")
 	}
 }
@@ -0,0 +1,29 @@
+package notify
+
+import "context"
+
+// plainTextKey is the context key for the plain-text alternative of a message.
+type plainTextKey struct{}
+
+// WithPlainText returns a copy of the given context that carries a plain-text alternative of the message, for HTML
+// messages. Mail services that support it send both versions as multipart/alternative message, so that text-only
+// clients show the given text instead of the raw HTML.
+func WithPlainText(ctx context.Context, text string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, plainTextKey{}, text)
+}
+
+// PlainTextFromContext returns the plain-text alternative of the message carried by the given context. It reports
+// false if the context carries none.
+func PlainTextFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+
+	text, ok := ctx.Value(plainTextKey{}).(string)
+
+	return text, ok
+}
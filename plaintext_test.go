@@ -0,0 +1,19 @@
+package notify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlainTextFromContext(t *testing.T) {
+	t.Parallel()
+
+	_, ok := PlainTextFromContext(context.Background())
+	require.False(t, ok)
+
+	text, ok := PlainTextFromContext(WithPlainText(context.Background(), "Disk full"))
+	require.True(t, ok)
+	require.Equal(t, "Disk full", text)
+}
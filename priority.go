@@ -0,0 +1,107 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Priority describes how urgently a notification should be delivered. Services translate it into the native priority
+// of their provider, e.g. the Pushover priority or the Urgency header of a web push message, so that urgent
+// notifications can break through Do Not Disturb.
+//
+// The values follow the 1 to 5 scale popularized by ntfy.
+type Priority int
+
+// These are the supported priorities, from least to most urgent.
+const (
+	PriorityMin Priority = iota + 1
+	PriorityLow
+	PriorityDefault
+	PriorityHigh
+	PriorityUrgent
+)
+
+// String returns the lower-case name of the priority.
+func (p Priority) String() string {
+	switch p {
+	case PriorityMin:
+		return "min"
+	case PriorityLow:
+		return "low"
+	case PriorityDefault:
+		return "default"
+	case PriorityHigh:
+		return "high"
+	case PriorityUrgent:
+		return "urgent"
+	}
+
+	return fmt.Sprintf("priority(%d)", int(p))
+}
+
+// ParsePriority returns the priority with the given name. The name is matched case-insensitively; the numbers 1 to 5
+// are accepted as well.
+func ParsePriority(name string) (Priority, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "min", "1":
+		return PriorityMin, nil
+	case "low", "2":
+		return PriorityLow, nil
+	case "default", "normal", "3":
+		return PriorityDefault, nil
+	case "high", "4":
+		return PriorityHigh, nil
+	case "urgent", "max", "5":
+		return PriorityUrgent, nil
+	}
+
+	return 0, fmt.Errorf("unknown priority %q", name)
+}
+
+// PriorityOf returns the priority that is used for notifications of the given severity, unless a priority is set
+// explicitly.
+func PriorityOf(severity Severity) Priority {
+	switch {
+	case severity <= SeverityDebug:
+		return PriorityMin
+	case severity <= SeverityWarning:
+		return PriorityDefault
+	case severity == SeverityError:
+		return PriorityHigh
+	}
+
+	return PriorityUrgent
+}
+
+// priorityKey is the context key for the priority of a notification.
+type priorityKey struct{}
+
+// WithPriority returns a copy of the given context that carries the given priority. Services that support it translate
+// the priority into the native priority of their provider.
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// PriorityFromContext returns the priority of the notification carried by the given context. If the context carries
+// no priority but a severity, the priority is derived from the severity using PriorityOf. It reports false if the
+// context carries neither; services should leave their provider's priority untouched in that case.
+func PriorityFromContext(ctx context.Context) (Priority, bool) {
+	if ctx == nil {
+		return PriorityDefault, false
+	}
+
+	if priority, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return priority, true
+	}
+
+	if severity, ok := SeverityFromContext(ctx); ok {
+		return PriorityOf(severity), true
+	}
+
+	return PriorityDefault, false
+}
@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePriority(t *testing.T) {
+	t.Parallel()
+
+	for _, priority := range []Priority{PriorityMin, PriorityLow, PriorityDefault, PriorityHigh, PriorityUrgent} {
+		got, err := ParsePriority(priority.String())
+		require.NoError(t, err)
+		require.Equal(t, priority, got)
+	}
+
+	got, err := ParsePriority("5")
+	require.NoError(t, err)
+	require.Equal(t, PriorityUrgent, got)
+
+	_, err = ParsePriority("whenever")
+	require.Error(t, err)
+}
+
+func TestPriorityFromContext(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		want   Priority
+		wantOK bool
+	}{
+		{
+			name: "Neither priority nor severity",
+			ctx:  context.Background(),
+			want: PriorityDefault,
+		},
+		{
+			name:   "Explicit priority",
+			ctx:    WithPriority(context.Background(), PriorityLow),
+			want:   PriorityLow,
+			wantOK: true,
+		},
+		{
+			name:   "Derived from severity",
+			ctx:    WithSeverity(context.Background(), SeverityCritical),
+			want:   PriorityUrgent,
+			wantOK: true,
+		},
+		{
+			name:   "Explicit priority takes precedence over severity",
+			ctx:    WithPriority(WithSeverity(context.Background(), SeverityCritical), PriorityMin),
+			want:   PriorityMin,
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := PriorityFromContext(tt.ctx)
+			require.Equal(t, tt.want, got)
+			require.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
@@ -0,0 +1,408 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TimeWindow is a recurring window of time during a day, e.g. from 22:00 to 07:00. Windows whose end is not after their
+// start span midnight.
+type TimeWindow struct {
+	// Start is the time of day the window starts at, as offset from midnight.
+	Start time.Duration
+
+	// End is the time of day the window ends at, as offset from midnight.
+	End time.Duration
+
+	// Days are the weekdays on which the window starts. An empty list means every day.
+	Days []time.Weekday
+}
+
+// ParseTimeWindow parses a time window in the form "22:00-07:00". It's active every day.
+func ParseTimeWindow(window string) (TimeWindow, error) {
+	start, end, ok := strings.Cut(window, "-")
+	if !ok {
+		return TimeWindow{}, fmt.Errorf("time window %q: expected format HH:MM-HH:MM", window)
+	}
+
+	startTime, err := time.Parse("15:04", strings.TrimSpace(start))
+	if err != nil {
+		return TimeWindow{}, fmt.Errorf("time window %q: parse start: %w", window, err)
+	}
+	endTime, err := time.Parse("15:04", strings.TrimSpace(end))
+	if err != nil {
+		return TimeWindow{}, fmt.Errorf("time window %q: parse end: %w", window, err)
+	}
+
+	return TimeWindow{
+		Start: time.Duration(startTime.Hour())*time.Hour + time.Duration(startTime.Minute())*time.Minute,
+		End:   time.Duration(endTime.Hour())*time.Hour + time.Duration(endTime.Minute())*time.Minute,
+	}, nil
+}
+
+// QuietHours is a calendar that describes when notifications are considered disruptive. A point in time is quiet if it
+// falls into one of the windows, on one of the quiet days, or on one of the holidays.
+type QuietHours struct {
+	// Location is the time zone the calendar is defined in, e.g. the result of time.LoadLocation("Europe/Berlin").
+	// Defaults to UTC.
+	Location *time.Location
+
+	// Windows are the recurring quiet windows, e.g. every night from 22:00 to 07:00.
+	Windows []TimeWindow
+
+	// Days are the weekdays that are quiet all day, e.g. time.Saturday and time.Sunday.
+	Days []time.Weekday
+
+	// Holidays are the dates that are quiet all day. Only the date in Location is used.
+	Holidays []time.Time
+}
+
+// maxQuietHoursSteps limits the search for the end of a quiet period, so that calendars that are always quiet don't
+// result in an endless loop.
+const maxQuietHoursSteps = 1000
+
+// location returns the time zone of the calendar.
+func (q QuietHours) location() *time.Location {
+	if q.Location == nil {
+		return time.UTC
+	}
+
+	return q.Location
+}
+
+// timeOfDay returns the given time of day on the given date.
+func timeOfDay(year int, month time.Month, day int, offset time.Duration, loc *time.Location) time.Time {
+	return time.Date(year, month, day, int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, loc)
+}
+
+// isQuietDay reports whether the given date is quiet all day.
+func (q QuietHours) isQuietDay(t time.Time) bool {
+	if slices.Contains(q.Days, t.Weekday()) {
+		return true
+	}
+
+	year, month, day := t.Date()
+	for _, holiday := range q.Holidays {
+		y, m, d := holiday.In(t.Location()).Date()
+		if y == year && m == month && d == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+// window returns the start and end of the given window if it starts on the date of the given time.
+func (w TimeWindow) window(t time.Time) (start, end time.Time, ok bool) {
+	if len(w.Days) > 0 && !slices.Contains(w.Days, t.Weekday()) {
+		return time.Time{}, time.Time{}, false
+	}
+
+	year, month, day := t.Date()
+	start = timeOfDay(year, month, day, w.Start, t.Location())
+	end = timeOfDay(year, month, day, w.End, t.Location())
+	if !end.After(start) {
+		end = timeOfDay(year, month, day+1, w.End, t.Location())
+	}
+
+	return start, end, true
+}
+
+// Active reports whether the given point in time is quiet.
+func (q QuietHours) Active(t time.Time) bool {
+	t = t.In(q.location())
+	if q.isQuietDay(t) {
+		return true
+	}
+
+	year, month, day := t.Date()
+	yesterday := time.Date(year, month, day-1, 12, 0, 0, 0, t.Location())
+
+	for _, w := range q.Windows {
+		// Check the window that starts today and the one that started yesterday, which might span midnight.
+		for _, date := range []time.Time{t, yesterday} {
+			start, end, ok := w.window(date)
+			if ok && !t.Before(start) && t.Before(end) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// End returns the first point in time at or after the given one that is not quiet. It reports false if no such point
+// could be found, e.g. because every day of the week is quiet.
+func (q QuietHours) End(t time.Time) (time.Time, bool) {
+	t = t.In(q.location())
+
+	for range maxQuietHoursSteps {
+		if !q.Active(t) {
+			return t, true
+		}
+
+		// Quiet periods can only end at midnight or at the end of a window.
+		year, month, day := t.Date()
+		next := time.Date(year, month, day+1, 0, 0, 0, 0, t.Location())
+		for _, w := range q.Windows {
+			for _, d := range []int{day - 1, day} {
+				_, end, ok := w.window(time.Date(year, month, d, 12, 0, 0, 0, t.Location()))
+				if ok && end.After(t) && end.Before(next) {
+					next = end
+				}
+			}
+		}
+		t = next
+	}
+
+	return time.Time{}, false
+}
+
+// QuietHoursAction defines what a QuietHoursPolicy does with notifications that are sent during quiet hours.
+type QuietHoursAction int
+
+// These are the supported actions.
+const (
+	// QuietHoursDefer holds back notifications until the quiet hours end and then sends them as a single digest.
+	QuietHoursDefer QuietHoursAction = iota
+
+	// QuietHoursSuppress drops notifications.
+	QuietHoursSuppress
+
+	// QuietHoursDowngrade sends notifications right away, but with a lower priority, so that they don't wake anyone up.
+	QuietHoursDowngrade
+)
+
+// QuietHoursOptions configure a QuietHoursPolicy.
+type QuietHoursOptions struct {
+	// Schedule defines when the quiet hours are.
+	Schedule QuietHours
+
+	// Action is what happens to notifications that are sent during quiet hours. Defaults to QuietHoursDefer.
+	Action QuietHoursAction
+
+	// BypassSeverity is the minimum severity a notification needs to be sent regardless of the quiet hours. Defaults
+	// to SeverityCritical if nil.
+	BypassSeverity *Severity
+
+	// DowngradePriority is the priority notifications are sent with during quiet hours when using QuietHoursDowngrade.
+	// Defaults to PriorityMin.
+	DowngradePriority Priority
+
+	// MaxDeferred is the maximum number of deferred notifications that are kept for the digest. Once it's reached, the
+	// oldest notifications are dropped, and their number is mentioned in the digest. This bounds the memory usage if
+	// the quiet hours never end. Defaults to 100.
+	MaxDeferred int
+
+	// RetryInterval is the delay before a digest that failed to be sent is retried. It doubles after every failed
+	// attempt, up to an hour. Defaults to one minute.
+	RetryInterval time.Duration
+
+	// OnError, if set, is called with the error of a digest that failed to be sent in the background.
+	OnError func(err error)
+}
+
+// These are the defaults of QuietHoursOptions and the limit of the retries of failed digests.
+const (
+	defaultQuietHoursBypassSeverity = SeverityCritical
+	defaultQuietHoursMaxDeferred    = 100
+	defaultQuietHoursRetryInterval  = time.Minute
+	maxQuietHoursRetryInterval      = time.Hour
+)
+
+// deferredMessage is a notification that was held back during quiet hours.
+type deferredMessage struct {
+	subject  string
+	message  string
+	severity Severity
+	sentAt   time.Time
+}
+
+// QuietHoursPolicy is a Notifier that wraps another Notifier and keeps non-critical notifications from disturbing its
+// receivers during quiet hours. Use one policy per group of receivers that shares the same calendar, e.g. one for the
+// Telegram chat of the European team and one for the Slack channel of the US team.
+//
+// Call Stop and then Flush on shutdown, so that no digest is sent in the background afterwards and no deferred
+// notification is lost. A QuietHoursPolicy is safe for concurrent use.
+type QuietHoursPolicy struct {
+	service Notifier
+	opts    QuietHoursOptions
+	bypass  Severity
+	now     func() time.Time
+
+	mu         sync.Mutex
+	deferred   []deferredMessage
+	dropped    int
+	timer      *time.Timer
+	retryDelay time.Duration
+	stopped    bool
+}
+
+// Compile-time check to ensure QuietHoursPolicy implements Notifier.
+var _ Notifier = (*QuietHoursPolicy)(nil)
+
+// NewQuietHoursPolicy returns a new QuietHoursPolicy that wraps the given service.
+func NewQuietHoursPolicy(service Notifier, opts QuietHoursOptions) *QuietHoursPolicy {
+	bypass := defaultQuietHoursBypassSeverity
+	if opts.BypassSeverity != nil {
+		bypass = *opts.BypassSeverity
+	}
+	if opts.MaxDeferred <= 0 {
+		opts.MaxDeferred = defaultQuietHoursMaxDeferred
+	}
+	if opts.DowngradePriority == 0 {
+		opts.DowngradePriority = PriorityMin
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = defaultQuietHoursRetryInterval
+	}
+
+	return &QuietHoursPolicy{
+		service: service,
+		opts:    opts,
+		bypass:  bypass,
+		now:     time.Now,
+	}
+}
+
+// Send sends the notification to the wrapped service, unless it's sent during quiet hours and its severity is lower
+// than the bypass severity. In that case, the notification is deferred, suppressed, or downgraded depending on the
+// configured action. Deferred and suppressed notifications are reported as sent successfully.
+func (p *QuietHoursPolicy) Send(ctx context.Context, subject, message string) error {
+	severity, _ := SeverityFromContext(ctx)
+	now := p.now()
+
+	if severity >= p.bypass || !p.opts.Schedule.Active(now) {
+		return p.service.Send(ctx, subject, message)
+	}
+
+	switch p.opts.Action {
+	case QuietHoursSuppress:
+		return nil
+	case QuietHoursDowngrade:
+		return p.service.Send(WithPriority(ctx, p.opts.DowngradePriority), subject, message)
+	case QuietHoursDefer:
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.deferred = append(p.deferred, deferredMessage{
+		subject:  subject,
+		message:  message,
+		severity: severity,
+		sentAt:   now,
+	})
+	p.trim()
+
+	// Schedule the digest for the end of the quiet hours. If they never end, the digest has to be flushed manually.
+	if end, ok := p.opts.Schedule.End(now); ok && p.timer == nil && !p.stopped {
+		p.timer = time.AfterFunc(end.Sub(now), p.flushInBackground)
+	}
+
+	return nil
+}
+
+// trim drops the oldest deferred notifications beyond MaxDeferred. The caller must hold the lock.
+func (p *QuietHoursPolicy) trim() {
+	if n := len(p.deferred) - p.opts.MaxDeferred; n > 0 {
+		p.deferred = slices.Delete(p.deferred, 0, n)
+		p.dropped += n
+	}
+}
+
+// Stop stops sending digests and retrying failed ones in the background. The deferred notifications are kept, so that
+// they can be sent by a final call to Flush, which doesn't schedule retries anymore.
+func (p *QuietHoursPolicy) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stopped = true
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+}
+
+// Pending returns the number of deferred notifications that have not been sent yet.
+func (p *QuietHoursPolicy) Pending() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.deferred)
+}
+
+// flushInBackground sends the digest once the quiet hours end.
+func (p *QuietHoursPolicy) flushInBackground() {
+	if err := p.Flush(context.Background()); err != nil && p.opts.OnError != nil {
+		p.opts.OnError(err)
+	}
+}
+
+// Flush sends all deferred notifications as a single digest right away, regardless of the quiet hours. It's useful to
+// not lose deferred notifications on shutdown. If sending the digest fails, the notifications are deferred again and
+// retried in the background, see QuietHoursOptions.RetryInterval, unless the policy was stopped.
+func (p *QuietHoursPolicy) Flush(ctx context.Context) error {
+	p.mu.Lock()
+	deferred, dropped := p.deferred, p.dropped
+	p.deferred, p.dropped = nil, 0
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	p.mu.Unlock()
+
+	if len(deferred) == 0 {
+		return nil
+	}
+
+	subject, message, severity := p.digest(deferred, dropped)
+
+	err := p.service.Send(WithSeverity(ctx, severity), subject, message)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err == nil {
+		p.retryDelay = 0
+		return nil
+	}
+
+	p.deferred = append(deferred, p.deferred...)
+	p.dropped += dropped
+	p.trim()
+
+	// Retry with an increasing delay, unless a notification that was deferred in the meantime scheduled the digest.
+	if p.timer == nil && !p.stopped {
+		p.retryDelay = min(max(2*p.retryDelay, p.opts.RetryInterval), maxQuietHoursRetryInterval)
+		p.timer = time.AfterFunc(p.retryDelay, p.flushInBackground)
+	}
+
+	return fmt.Errorf("send quiet hours digest: %w", err)
+}
+
+// digest combines the given deferred notifications into a single one and mentions the number of dropped ones, if any. A
+// single notification is passed on unchanged. The digest has the highest severity of the combined notifications.
+func (p *QuietHoursPolicy) digest(deferred []deferredMessage, dropped int) (subject, message string, severity Severity) {
+	if len(deferred) == 1 && dropped == 0 {
+		return deferred[0].subject, deferred[0].message, deferred[0].severity
+	}
+
+	loc := p.opts.Schedule.location()
+	parts := make([]string, 0, len(deferred))
+	for _, msg := range deferred {
+		severity = max(severity, msg.severity)
+		parts = append(parts, fmt.Sprintf("[%s] %s\n%s", msg.sentAt.In(loc).Format("Mon 15:04"), msg.subject, msg.message))
+	}
+
+	subject = fmt.Sprintf("%d notifications from quiet hours", len(deferred)+dropped)
+	if dropped > 0 {
+		parts = append(parts, fmt.Sprintf("%d older notifications were dropped.", dropped))
+	}
+
+	return subject, strings.Join(parts, "\n\n"), severity
+}
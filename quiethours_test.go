@@ -0,0 +1,279 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// subjectPriorityNotifier is a Notifier that records the subjects and priorities it was sent.
+type subjectPriorityNotifier struct {
+	subjects   []string
+	messages   []string
+	priorities []Priority
+}
+
+func (s *subjectPriorityNotifier) Send(ctx context.Context, subject, message string) error {
+	priority, _ := PriorityFromContext(ctx)
+	s.subjects = append(s.subjects, subject)
+	s.messages = append(s.messages, message)
+	s.priorities = append(s.priorities, priority)
+
+	return nil
+}
+
+// flakyNotifier is a Notifier that fails the given number of times before it passes notifications on to the next one.
+type flakyNotifier struct {
+	failures int32
+	calls    atomic.Int32
+	next     Notifier
+}
+
+func (f *flakyNotifier) Send(ctx context.Context, subject, message string) error {
+	if f.calls.Add(1) <= f.failures {
+		return errors.New("send failed")
+	}
+
+	return f.next.Send(ctx, subject, message)
+}
+
+func TestParseTimeWindow(t *testing.T) {
+	t.Parallel()
+
+	w, err := ParseTimeWindow("22:30 - 07:00")
+	require.NoError(t, err)
+	require.Equal(t, 22*time.Hour+30*time.Minute, w.Start)
+	require.Equal(t, 7*time.Hour, w.End)
+
+	_, err = ParseTimeWindow("22:30")
+	require.Error(t, err)
+
+	_, err = ParseTimeWindow("25:00-07:00")
+	require.Error(t, err)
+}
+
+func TestQuietHours(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	night, err := ParseTimeWindow("22:00-07:00")
+	require.NoError(t, err)
+
+	quiet := QuietHours{
+		Location: berlin,
+		Windows:  []TimeWindow{night},
+		Days:     []time.Weekday{time.Saturday, time.Sunday},
+		Holidays: []time.Time{time.Date(2024, 12, 25, 0, 0, 0, 0, berlin)},
+	}
+
+	tests := []struct {
+		name       string
+		at         time.Time
+		wantActive bool
+		wantEnd    time.Time
+	}{
+		{
+			name:       "Working hours",
+			at:         time.Date(2024, 3, 6, 12, 0, 0, 0, berlin), // Wednesday
+			wantActive: false,
+			wantEnd:    time.Date(2024, 3, 6, 12, 0, 0, 0, berlin),
+		},
+		{
+			name:       "Before midnight",
+			at:         time.Date(2024, 3, 6, 23, 0, 0, 0, berlin),
+			wantActive: true,
+			wantEnd:    time.Date(2024, 3, 7, 7, 0, 0, 0, berlin),
+		},
+		{
+			name:       "After midnight in another time zone",
+			at:         time.Date(2024, 3, 6, 22, 0, 0, 0, time.UTC), // 23:00 in Berlin
+			wantActive: true,
+			wantEnd:    time.Date(2024, 3, 7, 7, 0, 0, 0, berlin),
+		},
+		{
+			name:       "Friday night into the weekend",
+			at:         time.Date(2024, 3, 8, 23, 0, 0, 0, berlin),
+			wantActive: true,
+			wantEnd:    time.Date(2024, 3, 11, 7, 0, 0, 0, berlin),
+		},
+		{
+			name:       "Holiday",
+			at:         time.Date(2024, 12, 25, 12, 0, 0, 0, berlin),
+			wantActive: true,
+			wantEnd:    time.Date(2024, 12, 26, 7, 0, 0, 0, berlin),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.wantActive, quiet.Active(tt.at))
+
+			end, ok := quiet.End(tt.at)
+			require.True(t, ok)
+			require.True(t, tt.wantEnd.Equal(end), "want %s, got %s", tt.wantEnd, end)
+		})
+	}
+
+	alwaysQuiet := QuietHours{Days: []time.Weekday{
+		time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday,
+	}}
+	_, ok := alwaysQuiet.End(time.Now())
+	require.False(t, ok)
+}
+
+func TestQuietHoursPolicy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	quiet := QuietHours{Days: []time.Weekday{time.Sunday}}
+	sunday := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	monday := sunday.Add(24 * time.Hour)
+
+	t.Run("Defer", func(t *testing.T) {
+		t.Parallel()
+
+		service := &subjectPriorityNotifier{}
+		policy := NewQuietHoursPolicy(service, QuietHoursOptions{Schedule: quiet})
+		policy.now = func() time.Time { return sunday }
+
+		require.NoError(t, policy.Send(ctx, "first", "one"))
+		require.NoError(t, policy.Send(WithSeverity(ctx, SeverityError), "second", "two"))
+		require.NoError(t, policy.Send(WithSeverity(ctx, SeverityCritical), "critical", "three"))
+		require.Equal(t, []string{"critical"}, service.subjects)
+		require.Equal(t, 2, policy.Pending())
+
+		require.NoError(t, policy.Flush(ctx))
+		require.Equal(t, 0, policy.Pending())
+		require.Equal(t, []string{"critical", "2 notifications from quiet hours"}, service.subjects)
+		require.Equal(t, "[Sun 12:00] first\none\n\n[Sun 12:00] second\ntwo", service.messages[1])
+		require.Equal(t, PriorityHigh, service.priorities[1], "digest should have the highest severity")
+
+		policy.now = func() time.Time { return monday }
+		require.NoError(t, policy.Send(ctx, "monday", "message"))
+		require.Equal(t, "monday", service.subjects[2])
+	})
+
+	t.Run("Suppress", func(t *testing.T) {
+		t.Parallel()
+
+		service := &subjectPriorityNotifier{}
+		policy := NewQuietHoursPolicy(service, QuietHoursOptions{Schedule: quiet, Action: QuietHoursSuppress})
+		policy.now = func() time.Time { return sunday }
+
+		require.NoError(t, policy.Send(ctx, "suppressed", "message"))
+		require.Empty(t, service.subjects)
+		require.Equal(t, 0, policy.Pending())
+	})
+
+	t.Run("Downgrade", func(t *testing.T) {
+		t.Parallel()
+
+		service := &subjectPriorityNotifier{}
+		policy := NewQuietHoursPolicy(service, QuietHoursOptions{Schedule: quiet, Action: QuietHoursDowngrade})
+		policy.now = func() time.Time { return sunday }
+
+		require.NoError(t, policy.Send(WithSeverity(ctx, SeverityError), "downgraded", "message"))
+		require.Equal(t, []string{"downgraded"}, service.subjects)
+		require.Equal(t, []Priority{PriorityMin}, service.priorities)
+	})
+
+	t.Run("Failed digest is deferred again", func(t *testing.T) {
+		t.Parallel()
+
+		policy := NewQuietHoursPolicy(newFailingNotifier(), QuietHoursOptions{Schedule: quiet})
+		policy.now = func() time.Time { return sunday }
+
+		require.NoError(t, policy.Send(ctx, "deferred", "message"))
+		require.Error(t, policy.Flush(ctx))
+		require.Equal(t, 1, policy.Pending())
+		policy.Stop()
+	})
+
+	t.Run("Bypass all severities", func(t *testing.T) {
+		t.Parallel()
+
+		bypass := SeverityDebug
+		service := &subjectPriorityNotifier{}
+		policy := NewQuietHoursPolicy(service, QuietHoursOptions{Schedule: quiet, BypassSeverity: &bypass})
+		policy.now = func() time.Time { return sunday }
+
+		require.NoError(t, policy.Send(WithSeverity(ctx, SeverityDebug), "debug", "message"))
+		require.Equal(t, []string{"debug"}, service.subjects)
+	})
+
+	t.Run("Deferred notifications are capped", func(t *testing.T) {
+		t.Parallel()
+
+		service := &subjectPriorityNotifier{}
+		policy := NewQuietHoursPolicy(service, QuietHoursOptions{Schedule: quiet, MaxDeferred: 2})
+		policy.now = func() time.Time { return sunday }
+
+		for _, subject := range []string{"first", "second", "third"} {
+			require.NoError(t, policy.Send(ctx, subject, "message"))
+		}
+		require.Equal(t, 2, policy.Pending())
+
+		require.NoError(t, policy.Flush(ctx))
+		require.Equal(t, []string{"3 notifications from quiet hours"}, service.subjects)
+		require.Equal(t, "[Sun 12:00] second\nmessage\n\n[Sun 12:00] third\nmessage\n\n1 older notifications were dropped.",
+			service.messages[0])
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		t.Parallel()
+
+		policy := NewQuietHoursPolicy(newFailingNotifier(), QuietHoursOptions{Schedule: quiet})
+		policy.now = func() time.Time { return sunday }
+
+		require.NoError(t, policy.Send(ctx, "deferred", "message"))
+		require.Error(t, policy.Flush(ctx))
+
+		policy.Stop()
+		require.Equal(t, 1, policy.Pending(), "the backlog must be kept for a final flush")
+
+		// Neither new notifications nor failed flushes schedule digests anymore.
+		require.NoError(t, policy.Send(ctx, "after stop", "message"))
+		require.Error(t, policy.Flush(ctx))
+		require.Equal(t, 2, policy.Pending())
+
+		policy.mu.Lock()
+		defer policy.mu.Unlock()
+		require.Nil(t, policy.timer)
+	})
+
+	t.Run("Failed background digest is retried", func(t *testing.T) {
+		t.Parallel()
+
+		sent := make(channelNotifier, 1)
+		service := &flakyNotifier{failures: 2, next: sent}
+
+		errs := make(chan error, 2)
+		policy := NewQuietHoursPolicy(service, QuietHoursOptions{
+			Schedule:      quiet,
+			RetryInterval: 10 * time.Millisecond,
+			OnError:       func(err error) { errs <- err },
+		})
+		policy.now = func() time.Time { return sunday }
+
+		require.NoError(t, policy.Send(ctx, "deferred", "message"))
+
+		// The quiet hours end, but the digest fails twice before it's sent.
+		policy.flushInBackground()
+		require.Equal(t, "deferred", (<-sent).subject)
+		require.Equal(t, 0, policy.Pending())
+		require.Len(t, errs, 2)
+
+		policy.mu.Lock()
+		defer policy.mu.Unlock()
+		require.Nil(t, policy.timer)
+		require.Zero(t, policy.retryDelay)
+	})
+}
@@ -0,0 +1,165 @@
+package relay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+)
+
+// AllRoutes can be used in the routes of an APIKey to allow all routes.
+const AllRoutes = "*"
+
+// Config is the configuration of a Server.
+type Config struct {
+	// Services are the notification services the server forwards to.
+	Services []ServiceConfig `json:"services"`
+
+	// Routes map route names to the names of the services that receive messages sent to the route. Clients select
+	// routes using the tags of their requests.
+	Routes map[string][]string `json:"routes"`
+
+	// DefaultRoutes are the routes that receive requests without tags.
+	DefaultRoutes []string `json:"default_routes"`
+
+	// APIKeys are the keys that clients authenticate with.
+	APIKeys []APIKey `json:"api_keys"`
+}
+
+// ServiceConfig configures a single notification service.
+type ServiceConfig struct {
+	// Name uniquely identifies the service. It's used in routes and in the results of a request.
+	Name string `json:"name"`
+
+	// Type is the kind of service, e.g. "slack". It selects the Factory that creates the service.
+	Type string `json:"type"`
+
+	// MinSeverity is the minimum severity a message needs to be sent to the service, e.g. "critical".
+	MinSeverity string `json:"min_severity,omitempty"`
+
+	// Settings are passed to the Factory of the service.
+	Settings json.RawMessage `json:"settings,omitempty"`
+}
+
+// APIKey is a key that clients authenticate with.
+type APIKey struct {
+	// Name identifies the client, e.g. "ci".
+	Name string `json:"name"`
+
+	// Key is the secret the client sends as bearer token.
+	Key string `json:"key"`
+
+	// Routes are the routes the client may send to. Use AllRoutes to allow all routes.
+	Routes []string `json:"routes"`
+}
+
+// allows reports whether the key may send to the given route.
+func (k APIKey) allows(route string) bool {
+	return slices.Contains(k.Routes, AllRoutes) || slices.Contains(k.Routes, route)
+}
+
+// LoadConfig reads the JSON configuration at the given path. References to environment variables in the form ${VAR}
+// are expanded in string values, including those of the service settings, so that secrets don't need to be stored in
+// the file. Other uses of $ are kept as they are.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	// Expand the decoded values instead of the raw file, so that values of environment variables can't break or
+	// extend the JSON document.
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var raw any
+	if err = decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	if data, err = json.Marshal(expandEnv(raw)); err != nil {
+		return nil, fmt.Errorf("encode config: %w", err)
+	}
+
+	var cfg Config
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// envReference matches references to environment variables in the form ${VAR}.
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv replaces references to environment variables in all string values of the given decoded JSON value.
+func expandEnv(value any) any {
+	switch v := value.(type) {
+	case string:
+		return envReference.ReplaceAllStringFunc(v, func(ref string) string {
+			return os.Getenv(ref[2 : len(ref)-1])
+		})
+	case []any:
+		for i := range v {
+			v[i] = expandEnv(v[i])
+		}
+	case map[string]any:
+		for key := range v {
+			v[key] = expandEnv(v[key])
+		}
+	}
+
+	return value
+}
+
+// validate checks that all names are unique and all references can be resolved.
+func (c *Config) validate() error {
+	var errs []error
+
+	services := make(map[string]bool, len(c.Services))
+	for _, svc := range c.Services {
+		switch {
+		case svc.Name == "":
+			errs = append(errs, errors.New("service without name"))
+		case services[svc.Name]:
+			errs = append(errs, fmt.Errorf("duplicate service %q", svc.Name))
+		}
+		services[svc.Name] = true
+	}
+
+	for route, names := range c.Routes {
+		for _, name := range names {
+			if !services[name] {
+				errs = append(errs, fmt.Errorf("route %q: unknown service %q", route, name))
+			}
+		}
+	}
+
+	for _, route := range c.DefaultRoutes {
+		if _, ok := c.Routes[route]; !ok {
+			errs = append(errs, fmt.Errorf("default routes: unknown route %q", route))
+		}
+	}
+
+	keys := make(map[string]bool, len(c.APIKeys))
+	for _, key := range c.APIKeys {
+		switch {
+		case key.Key == "":
+			errs = append(errs, fmt.Errorf("api key %q: key is empty", key.Name))
+		case keys[key.Key]:
+			errs = append(errs, fmt.Errorf("api key %q: duplicate key", key.Name))
+		}
+		keys[key.Key] = true
+
+		for _, route := range key.Routes {
+			if _, ok := c.Routes[route]; !ok && route != AllRoutes {
+				errs = append(errs, fmt.Errorf("api key %q: unknown route %q", key.Name, route))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
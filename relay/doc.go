@@ -0,0 +1,31 @@
+/*
+Package relay provides a notification relay server that exposes notification services through an authenticated REST
+API. It allows applications that can't use this library directly, e.g. shell scripts or CI pipelines, to send
+notifications with a single HTTP request, without having to know the credentials of the services.
+
+The services, the routes that group them, and the API keys of the clients are loaded from a JSON configuration:
+
+	{
+	  "services": [
+	    {"name": "ops-slack", "type": "slack", "settings": {"token": "${SLACK_TOKEN}", "channels": ["C0123"]}},
+	    {"name": "oncall-pushover", "type": "pushover", "min_severity": "error", "settings": {"token": "${PUSHOVER_TOKEN}", "recipients": ["u123"]}}
+	  ],
+	  "routes": {
+	    "ops": ["ops-slack", "oncall-pushover"]
+	  },
+	  "default_routes": ["ops"],
+	  "api_keys": [
+	    {"name": "ci", "key": "${CI_API_KEY}", "routes": ["ops"]}
+	  ]
+	}
+
+Clients send messages to the routes selected by their tags:
+
+	curl -H "Authorization: Bearer $CI_API_KEY" \
+	    -d '{"subject": "Deploy failed", "message": "Build #42 failed", "tags": ["ops"], "severity": "error"}' \
+	    http://localhost:8080/v1/send
+
+The response holds a result for every service the message was routed to. The cmd/notifyd command runs the server with
+factories for the most common services.
+*/
+package relay
@@ -0,0 +1,338 @@
+package relay
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/nikoksr/notify"
+)
+
+// These are the limits of a send request.
+const (
+	maxBodyBytes     = 1 << 20
+	maxSubjectLength = 1024
+	maxMessageLength = 64 << 10
+)
+
+// These are the statuses of a ServiceResult.
+const (
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// Factory creates a notification service from its settings.
+type Factory func(settings json.RawMessage) (notify.Notifier, error)
+
+// SendRequest is the body of a request to POST /v1/send.
+type SendRequest struct {
+	// Subject is the subject of the message.
+	Subject string `json:"subject"`
+
+	// Message is the body of the message. It's required.
+	Message string `json:"message"`
+
+	// Tags select the routes the message is sent to. Requests without tags are sent to the default routes.
+	Tags []string `json:"tags,omitempty"`
+
+	// Severity is the severity of the message, e.g. "critical". Defaults to "info".
+	Severity string `json:"severity,omitempty"`
+
+	// Priority is the priority of the message, e.g. "high". Defaults to the priority derived from the severity.
+	Priority string `json:"priority,omitempty"`
+}
+
+// SendResponse is the body of a response to POST /v1/send.
+type SendResponse struct {
+	// Results hold the outcome for every service the message was routed to.
+	Results []ServiceResult `json:"results"`
+}
+
+// ServiceResult is the outcome of sending a message to a single service.
+type ServiceResult struct {
+	// Service is the name of the service.
+	Service string `json:"service"`
+
+	// Status is one of StatusSent, StatusFailed or StatusSkipped. Services are skipped if the severity of the message
+	// is below their minimum severity.
+	Status string `json:"status"`
+
+	// Error is the error message of a failed send.
+	Error string `json:"error,omitempty"`
+
+	// Retryable reports whether sending the message again might succeed.
+	Retryable bool `json:"retryable,omitempty"`
+}
+
+// errorResponse is the body of all responses to invalid requests.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// apiKey is an APIKey together with the hash of its key.
+type apiKey struct {
+	APIKey
+
+	hash [sha256.Size]byte
+}
+
+// Server is an http.Handler that exposes the configured notification services through a REST API. Clients
+// authenticate with one of the configured API keys as bearer token and may only send to the routes of their key.
+//
+// It serves the following endpoints:
+//
+//	POST /v1/send  sends a message, see SendRequest and SendResponse
+//	GET  /healthz  reports that the server is up
+type Server struct {
+	cfg    *Config
+	notify *notify.Notify
+	keys   []apiKey
+	mux    *http.ServeMux
+}
+
+// Compile-time check to ensure Server implements http.Handler.
+var _ http.Handler = (*Server)(nil)
+
+// NewServer returns a new Server for the given configuration. The services are created using the factory registered
+// for their type.
+func NewServer(cfg *Config, factories map[string]Factory) (*Server, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	n := notify.New()
+	for _, svc := range cfg.Services {
+		factory, ok := factories[svc.Type]
+		if !ok {
+			return nil, fmt.Errorf("service %q: unsupported type %q", svc.Name, svc.Type)
+		}
+
+		service, err := factory(svc.Settings)
+		if err != nil {
+			return nil, fmt.Errorf("service %q: %w", svc.Name, err)
+		}
+
+		var options []notify.ServiceOption
+		if svc.MinSeverity != "" {
+			severity, err := notify.ParseSeverity(svc.MinSeverity)
+			if err != nil {
+				return nil, fmt.Errorf("service %q: %w", svc.Name, err)
+			}
+			options = append(options, notify.MinSeverity(severity))
+		}
+
+		if err = n.UseNamedService(svc.Name, service, options...); err != nil {
+			return nil, fmt.Errorf("service %q: %w", svc.Name, err)
+		}
+	}
+
+	s := &Server{
+		cfg:    cfg,
+		notify: n,
+		mux:    http.NewServeMux(),
+	}
+	for _, key := range cfg.APIKeys {
+		s.keys = append(s.keys, apiKey{APIKey: key, hash: sha256.Sum256([]byte(key.Key))})
+	}
+
+	s.mux.HandleFunc("POST /v1/send", s.handleSend)
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+
+	return s, nil
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// writeJSON writes the given value as JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an errorResponse with the given status code.
+func writeError(w http.ResponseWriter, status int, format string, args ...any) {
+	writeJSON(w, status, errorResponse{Error: fmt.Sprintf(format, args...)})
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// authenticate returns the API key the request was made with. The keys are compared by their hashes in constant time.
+func (s *Server) authenticate(r *http.Request) (APIKey, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return APIKey{}, false
+	}
+
+	hash := sha256.Sum256([]byte(token))
+	for _, key := range s.keys {
+		if subtle.ConstantTimeCompare(hash[:], key.hash[:]) == 1 {
+			return key.APIKey, true
+		}
+	}
+
+	return APIKey{}, false
+}
+
+// decodeRequest decodes and validates the body of a send request. It returns the context to send the message with.
+func decodeRequest(r *http.Request, w http.ResponseWriter) (SendRequest, context.Context, error) {
+	var req SendRequest
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		return req, nil, fmt.Errorf("decode request: %w", err)
+	}
+
+	var errs []error
+	if strings.TrimSpace(req.Message) == "" {
+		errs = append(errs, errors.New("message is required"))
+	}
+	if len(req.Subject) > maxSubjectLength {
+		errs = append(errs, fmt.Errorf("subject is longer than %d bytes", maxSubjectLength))
+	}
+	if len(req.Message) > maxMessageLength {
+		errs = append(errs, fmt.Errorf("message is longer than %d bytes", maxMessageLength))
+	}
+
+	ctx := r.Context()
+	if req.Severity != "" {
+		severity, err := notify.ParseSeverity(req.Severity)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		ctx = notify.WithSeverity(ctx, severity)
+	}
+	if req.Priority != "" {
+		priority, err := notify.ParsePriority(req.Priority)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		ctx = notify.WithPriority(ctx, priority)
+	}
+
+	return req, ctx, errors.Join(errs...)
+}
+
+// resolveServices returns the set of names of the services of the given routes.
+func (s *Server) resolveServices(routes []string) map[string]bool {
+	names := make(map[string]bool)
+	for _, route := range routes {
+		for _, name := range s.cfg.Routes[route] {
+			names[name] = true
+		}
+	}
+
+	return names
+}
+
+func (s *Server) handleSend(w http.ResponseWriter, r *http.Request) {
+	key, ok := s.authenticate(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, "missing or invalid API key")
+		return
+	}
+
+	req, ctx, err := decodeRequest(r, w)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, "request body is larger than %d bytes", maxBodyBytes)
+			return
+		}
+
+		writeError(w, http.StatusBadRequest, "%s", strings.ReplaceAll(err.Error(), "\n", "; "))
+		return
+	}
+
+	routes := req.Tags
+	if len(routes) == 0 {
+		routes = s.cfg.DefaultRoutes
+	}
+	if len(routes) == 0 {
+		writeError(w, http.StatusBadRequest, "no tags given and no default routes configured")
+		return
+	}
+	for _, route := range routes {
+		if _, ok := s.cfg.Routes[route]; !ok {
+			writeError(w, http.StatusBadRequest, "unknown tag %q", route)
+			return
+		}
+		if !key.allows(route) {
+			writeError(w, http.StatusForbidden, "API key %q may not send to %q", key.Name, route)
+			return
+		}
+	}
+
+	results := s.send(ctx, s.resolveServices(routes), req.Subject, req.Message)
+
+	writeJSON(w, statusOf(results), SendResponse{Results: results})
+}
+
+// send sends the message to the services with the given names concurrently and returns a result for each of them.
+func (s *Server) send(ctx context.Context, names map[string]bool, subject, message string) []ServiceResult {
+	severity, _ := notify.SeverityFromContext(ctx)
+
+	var services []notify.ServiceInfo
+	for _, info := range s.notify.Services() {
+		if names[info.Name] {
+			services = append(services, info)
+		}
+	}
+
+	results := make([]ServiceResult, len(services))
+
+	var wg sync.WaitGroup
+	for i, info := range services {
+		results[i].Service = info.Name
+
+		if info.Disabled || severity < info.MinSeverity {
+			results[i].Status = StatusSkipped
+			continue
+		}
+
+		wg.Go(func() {
+			if err := info.Service.Send(ctx, subject, message); err != nil {
+				results[i].Status = StatusFailed
+				results[i].Error = err.Error()
+				results[i].Retryable = notify.IsRetryable(err)
+				return
+			}
+
+			results[i].Status = StatusSent
+		})
+	}
+	wg.Wait()
+
+	return results
+}
+
+// statusOf returns the status code of a response with the given results: 200 if no service failed, 502 if all services
+// that were attempted failed, and 207 otherwise.
+func statusOf(results []ServiceResult) int {
+	attempted := slices.DeleteFunc(slices.Clone(results), func(r ServiceResult) bool { return r.Status == StatusSkipped })
+	failed := slices.DeleteFunc(slices.Clone(attempted), func(r ServiceResult) bool { return r.Status != StatusFailed })
+
+	switch {
+	case len(failed) == 0:
+		return http.StatusOK
+	case len(failed) == len(attempted):
+		return http.StatusBadGateway
+	}
+
+	return http.StatusMultiStatus
+}
@@ -0,0 +1,358 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/notify"
+)
+
+// fakeService is a notify.Notifier that records the messages it was sent, or fails with the configured error.
+type fakeService struct {
+	mu       sync.Mutex
+	messages []string
+	err      error
+}
+
+func (f *fakeService) Send(_ context.Context, subject, message string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return f.err
+	}
+	f.messages = append(f.messages, subject+": "+message)
+
+	return nil
+}
+
+func (f *fakeService) sent() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.messages
+}
+
+// newTestServer returns a server with the services "slack", "pager", and "broken", where "pager" only receives errors
+// and "broken" always fails with a retryable error.
+func newTestServer(t *testing.T) (*Server, map[string]*fakeService) {
+	t.Helper()
+
+	services := map[string]*fakeService{
+		"slack":  {},
+		"pager":  {},
+		"broken": {err: notify.NewReceiverError("fake", "r", errors.New("boom")).SetStatusCode(http.StatusServiceUnavailable)},
+	}
+
+	cfg := &Config{
+		Services: []ServiceConfig{
+			{Name: "slack", Type: "fake", Settings: json.RawMessage(`"slack"`)},
+			{Name: "pager", Type: "fake", MinSeverity: "error", Settings: json.RawMessage(`"pager"`)},
+			{Name: "broken", Type: "fake", Settings: json.RawMessage(`"broken"`)},
+		},
+		Routes: map[string][]string{
+			"ops":    {"slack", "pager"},
+			"alerts": {"pager"},
+			"flaky":  {"slack", "broken"},
+			"down":   {"broken"},
+		},
+		DefaultRoutes: []string{"ops"},
+		APIKeys: []APIKey{
+			{Name: "ci", Key: "ci-key", Routes: []string{"ops"}},
+			{Name: "admin", Key: "admin-key", Routes: []string{AllRoutes}},
+		},
+	}
+
+	factories := map[string]Factory{
+		"fake": func(settings json.RawMessage) (notify.Notifier, error) {
+			var name string
+			if err := json.Unmarshal(settings, &name); err != nil {
+				return nil, err
+			}
+
+			return services[name], nil
+		},
+	}
+
+	server, err := NewServer(cfg, factories)
+	require.NoError(t, err)
+
+	return server, services
+}
+
+// send posts the given body to the server and returns the response.
+func send(t *testing.T, server *Server, key, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/send", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func decodeResults(t *testing.T, rec *httptest.ResponseRecorder) []ServiceResult {
+	t.Helper()
+
+	var resp SendResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+
+	return resp.Results
+}
+
+func TestServer_Send(t *testing.T) {
+	t.Parallel()
+
+	server, services := newTestServer(t)
+
+	rec := send(t, server, "ci-key", `{"subject": "Deploy", "message": "done", "tags": ["ops"]}`)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	require.Equal(t, []ServiceResult{
+		{Service: "slack", Status: StatusSent},
+		{Service: "pager", Status: StatusSkipped},
+	}, decodeResults(t, rec))
+	require.Equal(t, []string{"Deploy: done"}, services["slack"].sent())
+	require.Empty(t, services["pager"].sent())
+}
+
+func TestServer_SendWithSeverity(t *testing.T) {
+	t.Parallel()
+
+	server, services := newTestServer(t)
+
+	rec := send(t, server, "ci-key", `{"subject": "Deploy", "message": "failed", "severity": "error"}`)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, []ServiceResult{
+		{Service: "slack", Status: StatusSent},
+		{Service: "pager", Status: StatusSent},
+	}, decodeResults(t, rec))
+	require.Equal(t, []string{"Deploy: failed"}, services["pager"].sent())
+}
+
+func TestServer_SendFailures(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newTestServer(t)
+
+	rec := send(t, server, "admin-key", `{"message": "hi", "tags": ["flaky"]}`)
+	require.Equal(t, http.StatusMultiStatus, rec.Code)
+	require.Equal(t, []ServiceResult{
+		{Service: "slack", Status: StatusSent},
+		{Service: "broken", Status: StatusFailed, Error: "boom", Retryable: true},
+	}, decodeResults(t, rec))
+
+	rec = send(t, server, "admin-key", `{"message": "hi", "tags": ["down"]}`)
+	require.Equal(t, http.StatusBadGateway, rec.Code)
+}
+
+func TestServer_InvalidRequests(t *testing.T) {
+	t.Parallel()
+
+	server, services := newTestServer(t)
+
+	tests := []struct {
+		name       string
+		key        string
+		body       string
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name:       "missing key",
+			body:       `{"message": "hi"}`,
+			wantStatus: http.StatusUnauthorized,
+			wantError:  "missing or invalid API key",
+		},
+		{
+			name:       "invalid key",
+			key:        "nope",
+			body:       `{"message": "hi"}`,
+			wantStatus: http.StatusUnauthorized,
+			wantError:  "missing or invalid API key",
+		},
+		{
+			name:       "route not allowed",
+			key:        "ci-key",
+			body:       `{"message": "hi", "tags": ["alerts"]}`,
+			wantStatus: http.StatusForbidden,
+			wantError:  `API key "ci" may not send to "alerts"`,
+		},
+		{
+			name:       "unknown tag",
+			key:        "admin-key",
+			body:       `{"message": "hi", "tags": ["nope"]}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  `unknown tag "nope"`,
+		},
+		{
+			name:       "missing message",
+			key:        "ci-key",
+			body:       `{"subject": "hi"}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "message is required",
+		},
+		{
+			name:       "invalid severity",
+			key:        "ci-key",
+			body:       `{"message": "hi", "severity": "loud"}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  `unknown severity "loud"`,
+		},
+		{
+			name:       "unknown field",
+			key:        "ci-key",
+			body:       `{"message": "hi", "channel": "C1"}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  `unknown field "channel"`,
+		},
+		{
+			name:       "body too large",
+			key:        "ci-key",
+			body:       `{"message": "` + strings.Repeat("a", maxBodyBytes) + `"}`,
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantError:  "request body is larger than",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := send(t, server, tt.key, tt.body)
+			require.Equal(t, tt.wantStatus, rec.Code)
+
+			var resp errorResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			require.Contains(t, resp.Error, tt.wantError)
+		})
+	}
+
+	require.Empty(t, services["slack"].sent())
+	require.Empty(t, services["pager"].sent())
+}
+
+func TestServer_Health(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"status": "ok"}`, rec.Body.String())
+}
+
+func TestNewServer_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	factories := map[string]Factory{
+		"fake": func(json.RawMessage) (notify.Notifier, error) { return &fakeService{}, nil },
+	}
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name:    "duplicate service",
+			cfg:     Config{Services: []ServiceConfig{{Name: "a", Type: "fake"}, {Name: "a", Type: "fake"}}},
+			wantErr: `duplicate service "a"`,
+		},
+		{
+			name:    "unknown service in route",
+			cfg:     Config{Routes: map[string][]string{"ops": {"a"}}},
+			wantErr: `route "ops": unknown service "a"`,
+		},
+		{
+			name:    "unknown default route",
+			cfg:     Config{DefaultRoutes: []string{"ops"}},
+			wantErr: `default routes: unknown route "ops"`,
+		},
+		{
+			name:    "empty api key",
+			cfg:     Config{APIKeys: []APIKey{{Name: "ci"}}},
+			wantErr: `api key "ci": key is empty`,
+		},
+		{
+			name:    "unsupported type",
+			cfg:     Config{Services: []ServiceConfig{{Name: "a", Type: "carrier-pigeon"}}},
+			wantErr: `service "a": unsupported type "carrier-pigeon"`,
+		},
+		{
+			name:    "invalid min severity",
+			cfg:     Config{Services: []ServiceConfig{{Name: "a", Type: "fake", MinSeverity: "loud"}}},
+			wantErr: `service "a": unknown severity "loud"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewServer(&tt.cfg, factories)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("RELAY_TEST_KEY", "secret")
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"services": [{"name": "slack", "type": "slack", "settings": {"token": "x"}}],
+		"routes": {"ops": ["slack"]},
+		"api_keys": [{"name": "ci", "key": "${RELAY_TEST_KEY}", "routes": ["*"]}]
+	}`), 0o600))
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, "secret", cfg.APIKeys[0].Key)
+	require.JSONEq(t, `{"token": "x"}`, string(cfg.Services[0].Settings))
+	require.NoError(t, cfg.validate())
+}
+
+func TestLoadConfig_Expansion(t *testing.T) {
+	t.Setenv("RELAY_TEST_TOKEN", `abc"def\`)
+	t.Setenv("RELAY_TEST_INJECT", `x", "routes": ["*"], "name": "y`)
+	t.Setenv("PASS", "expanded")
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"services": [{"name": "slack", "type": "slack", "settings": {"token": "${RELAY_TEST_TOKEN}", "password": "pa$PASS$$"}}],
+		"routes": {"ops": ["slack"]},
+		"api_keys": [{"name": "ci", "key": "${RELAY_TEST_INJECT}", "routes": ["ops"]}]
+	}`), 0o600))
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+
+	// Values are taken literally and can't add keys to the configuration.
+	require.Equal(t, `x", "routes": ["*"], "name": "y`, cfg.APIKeys[0].Key)
+	require.Equal(t, "ci", cfg.APIKeys[0].Name)
+	require.Equal(t, []string{"ops"}, cfg.APIKeys[0].Routes)
+
+	// Only ${VAR} is expanded, a bare $ is kept.
+	var settings map[string]string
+	require.NoError(t, json.Unmarshal(cfg.Services[0].Settings, &settings))
+	require.Equal(t, map[string]string{"token": `abc"def\`, "password": "pa$PASS$$"}, settings)
+}
@@ -0,0 +1,197 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PanicError is the error a Reporter reports for a function that panicked.
+type PanicError struct {
+	// Value is the value the function panicked with.
+	Value any
+
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+// Error returns the panic value.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it's an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+
+	return err
+}
+
+// ReporterOptions configure a Reporter.
+type ReporterOptions struct {
+	// Hostname is the name of the host that is included in all reports. Defaults to the result of os.Hostname.
+	Hostname string
+
+	// ReportSuccess enables reports for jobs that finished successfully. They include the duration of the job and a few
+	// runtime statistics, e.g. the memory usage.
+	ReportSuccess bool
+
+	// OnError, if set, is called with the errors of reports that failed to be sent.
+	OnError func(err error)
+}
+
+// Reporter sends notifications about goroutines, HTTP handlers, and jobs that fail or panic. It replaces the
+// defer/recover block around Send that is otherwise needed in every program.
+//
+// Failures are reported with SeverityError, panics with SeverityCritical, and successes with SeverityInfo.
+type Reporter struct {
+	notifier Notifier
+	opts     ReporterOptions
+	now      func() time.Time
+
+	mu      sync.Mutex
+	waiting bool
+	wg      sync.WaitGroup
+}
+
+// NewReporter returns a new Reporter that sends its reports to the given notifier, usually a *Notify.
+func NewReporter(notifier Notifier, opts ReporterOptions) *Reporter {
+	if opts.Hostname == "" {
+		opts.Hostname, _ = os.Hostname()
+	}
+
+	return &Reporter{
+		notifier: notifier,
+		opts:     opts,
+		now:      time.Now,
+	}
+}
+
+// Run runs the given job and reports its outcome. A panic is recovered and returned as *PanicError. The error of the job
+// is returned unchanged; errors of the report are passed to OnError.
+func (r *Reporter) Run(ctx context.Context, name string, job func(ctx context.Context) error) (err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	start := r.now()
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+
+		r.report(ctx, name, start, err)
+	}()
+
+	return job(ctx)
+}
+
+// Go runs the given function in a new goroutine and reports its outcome like Run. A panic is recovered instead of
+// crashing the program. Use Run within a sync.WaitGroup to wait for the goroutine.
+func (r *Reporter) Go(ctx context.Context, name string, fn func(ctx context.Context) error) {
+	go func() {
+		_ = r.Run(ctx, name, fn) // Reported already.
+	}()
+}
+
+// Handler returns an http.Handler that recovers panics of the given handler, answers them with status 500, and
+// reports them. The report includes the method and URL of the request and is sent in the background, so that a slow
+// notifier doesn't delay the response; use Wait to wait for pending reports. Panics with http.ErrAbortHandler are
+// passed on, as they are used to abort a response on purpose.
+func (r *Reporter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		start := r.now()
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if err, ok := v.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(v)
+			}
+
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+			err := &PanicError{Value: v, Stack: debug.Stack()}
+			r.reportInBackground(req.Context(), req.Method+" "+req.URL.String(), start, err)
+		}()
+
+		next.ServeHTTP(w, req)
+	})
+}
+
+// Wait waits until the reports of panicked handlers that are sent in the background have been sent, e.g. before the
+// program exits. Reports of handlers that panic afterwards are sent before the response is completed.
+func (r *Reporter) Wait() {
+	r.mu.Lock()
+	r.waiting = true
+	r.mu.Unlock()
+
+	r.wg.Wait()
+}
+
+// reportInBackground sends the report about the job with the given name in the background, unless Wait was called.
+func (r *Reporter) reportInBackground(ctx context.Context, name string, start time.Time, err error) {
+	r.mu.Lock()
+	waiting := r.waiting
+	if !waiting {
+		r.wg.Go(func() { r.report(ctx, name, start, err) })
+	}
+	r.mu.Unlock()
+
+	if waiting {
+		r.report(ctx, name, start, err)
+	}
+}
+
+// report sends the report about the job with the given name. Successful jobs are only reported if enabled.
+func (r *Reporter) report(ctx context.Context, name string, start time.Time, err error) {
+	if err == nil && !r.opts.ReportSuccess {
+		return
+	}
+
+	duration := r.now().Sub(start)
+
+	var subject string
+	var details strings.Builder
+	severity := SeverityError
+
+	var panicErr *PanicError
+	switch {
+	case errors.As(err, &panicErr):
+		severity = SeverityCritical
+		subject = fmt.Sprintf("%s panicked on %s", name, r.opts.Hostname)
+		fmt.Fprintf(&details, "Error: %v\n", panicErr)
+	case err != nil:
+		subject = fmt.Sprintf("%s failed on %s", name, r.opts.Hostname)
+		fmt.Fprintf(&details, "Error: %v\n", err)
+	default:
+		severity = SeverityInfo
+		subject = fmt.Sprintf("%s succeeded on %s", name, r.opts.Hostname)
+	}
+
+	fmt.Fprintf(&details, "Host: %s\nStarted: %s\nDuration: %s", r.opts.Hostname, start.Format(time.RFC3339), duration)
+
+	if err == nil {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		fmt.Fprintf(&details, "\nGoroutines: %d\nHeap: %d KiB\nGC cycles: %d",
+			runtime.NumGoroutine(), stats.HeapAlloc>>10, stats.NumGC)
+	}
+
+	if panicErr != nil {
+		fmt.Fprintf(&details, "\n\nStack trace:\n%s", panicErr.Stack)
+	}
+
+	ctx = WithSeverity(context.WithoutCancel(ctx), severity)
+	if sendErr := r.notifier.Send(ctx, subject, details.String()); sendErr != nil && r.opts.OnError != nil {
+		r.opts.OnError(fmt.Errorf("send report of %s: %w", name, sendErr))
+	}
+}
@@ -0,0 +1,154 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReporter(ch logNotifier, opts ReporterOptions) *Reporter {
+	opts.Hostname = "worker-1"
+	reporter := NewReporter(ch, opts)
+
+	now := time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC)
+	reporter.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	return reporter
+}
+
+func TestReporter_Run(t *testing.T) {
+	t.Parallel()
+
+	ch := make(logNotifier, 1)
+	reporter := newTestReporter(ch, ReporterOptions{})
+
+	jobErr := errors.New("database unreachable")
+	err := reporter.Run(context.Background(), "backup", func(context.Context) error {
+		return jobErr
+	})
+	require.ErrorIs(t, err, jobErr)
+
+	got := receiveLog(t, ch)
+	require.Equal(t, "backup failed on worker-1", got.subject)
+	require.Equal(t, "Error: database unreachable\nHost: worker-1\nStarted: 2024-03-06T12:00:01Z\nDuration: 1s", got.message)
+	require.Equal(t, SeverityError, got.severity)
+
+	// Successes are not reported by default.
+	require.NoError(t, reporter.Run(context.Background(), "backup", func(context.Context) error { return nil }))
+	requireNoLog(t, ch)
+}
+
+func TestReporter_RunPanic(t *testing.T) {
+	t.Parallel()
+
+	ch := make(logNotifier, 1)
+	reporter := newTestReporter(ch, ReporterOptions{})
+
+	err := reporter.Run(context.Background(), "cleanup", func(context.Context) error {
+		panic("nil map")
+	})
+
+	var panicErr *PanicError
+	require.ErrorAs(t, err, &panicErr)
+	require.Equal(t, "nil map", panicErr.Value)
+
+	got := receiveLog(t, ch)
+	require.Equal(t, "cleanup panicked on worker-1", got.subject)
+	require.Contains(t, got.message, "Error: panic: nil map\n")
+	require.Contains(t, got.message, "Stack trace:\ngoroutine")
+	require.Equal(t, SeverityCritical, got.severity)
+}
+
+func TestReporter_ReportSuccess(t *testing.T) {
+	t.Parallel()
+
+	ch := make(logNotifier, 1)
+	reporter := newTestReporter(ch, ReporterOptions{ReportSuccess: true})
+
+	require.NoError(t, reporter.Run(context.Background(), "backup", func(context.Context) error { return nil }))
+
+	got := receiveLog(t, ch)
+	require.Equal(t, "backup succeeded on worker-1", got.subject)
+	require.Contains(t, got.message, "Duration: 1s\nGoroutines: ")
+	require.Equal(t, SeverityInfo, got.severity)
+}
+
+func TestReporter_Go(t *testing.T) {
+	t.Parallel()
+
+	ch := make(logNotifier, 1)
+	reporter := newTestReporter(ch, ReporterOptions{})
+
+	reporter.Go(context.Background(), "consumer", func(context.Context) error {
+		var m map[string]int
+		m["x"]++
+
+		return nil
+	})
+
+	got := receiveLog(t, ch)
+	require.Equal(t, "consumer panicked on worker-1", got.subject)
+	require.Contains(t, got.message, "assignment to entry in nil map")
+}
+
+func TestReporter_OnError(t *testing.T) {
+	t.Parallel()
+
+	var reportErr error
+	reporter := NewReporter(newFailingNotifier(), ReporterOptions{OnError: func(err error) { reportErr = err }})
+
+	_ = reporter.Run(context.Background(), "backup", func(context.Context) error { return errors.New("boom") })
+	require.EqualError(t, reportErr, "send report of backup: send failed")
+}
+
+func TestReporter_Handler(t *testing.T) {
+	t.Parallel()
+
+	// The notifier blocks until the report is received, so the response must not wait for it.
+	ch := make(logNotifier)
+	reporter := newTestReporter(ch, ReporterOptions{})
+
+	handler := reporter.Handler(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/abort" {
+			panic(http.ErrAbortHandler)
+		}
+		if r.URL.Path == "/panic" {
+			panic(errors.New("index out of range"))
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ok", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	requireNoLog(t, ch)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/panic?id=1", nil))
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	got := receiveLog(t, ch)
+	require.Equal(t, "POST /panic?id=1 panicked on worker-1", got.subject)
+	require.Contains(t, got.message, "Error: panic: index out of range\n")
+	reporter.Wait()
+
+	// Once Wait was called, reports are sent before the response is completed.
+	buffered := make(logNotifier, 1)
+	waited := newTestReporter(buffered, ReporterOptions{})
+	waited.Wait()
+	waited.Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic("boom") })).
+		ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Len(t, buffered, 1)
+
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/abort", nil))
+	})
+	requireNoLog(t, ch)
+}
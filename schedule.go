@@ -0,0 +1,420 @@
+package notify
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrScheduledMessageNotFound signals that no scheduled message with the given ID is pending.
+var ErrScheduledMessageNotFound = errors.New("scheduled message not found")
+
+// ScheduledMessage is a notification that is going to be sent at a later point in time.
+type ScheduledMessage struct {
+	// ID uniquely identifies the message. It's used to cancel the message.
+	ID string `json:"id"`
+
+	// At is the point in time the message is going to be sent at.
+	At time.Time `json:"at"`
+
+	// Subject is the subject of the message.
+	Subject string `json:"subject"`
+
+	// Message is the body of the message.
+	Message string `json:"message"`
+
+	// Severity is the severity the message was scheduled with, if any.
+	Severity *Severity `json:"severity,omitempty"`
+
+	// Priority is the priority the message was scheduled with, if any.
+	Priority *Priority `json:"priority,omitempty"`
+}
+
+// context returns a new context that carries the severity and priority of the message.
+func (m ScheduledMessage) context() context.Context {
+	ctx := context.Background()
+	if m.Severity != nil {
+		ctx = WithSeverity(ctx, *m.Severity)
+	}
+	if m.Priority != nil {
+		ctx = WithPriority(ctx, *m.Priority)
+	}
+
+	return ctx
+}
+
+// ScheduleStore persists scheduled messages, so that they survive restarts of the process.
+type ScheduleStore interface {
+	// Save stores the given message.
+	Save(ctx context.Context, msg ScheduledMessage) error
+
+	// Delete removes the message with the given ID. Deleting a message that does not exist is not an error.
+	Delete(ctx context.Context, id string) error
+
+	// List returns all stored messages.
+	List(ctx context.Context) ([]ScheduledMessage, error)
+}
+
+// SchedulerOptions configure a Scheduler.
+type SchedulerOptions struct {
+	// Store, if set, persists scheduled messages. Without a store, pending messages are lost when the process exits.
+	Store ScheduleStore
+
+	// OnError, if set, is called with the error of a scheduled message that failed to be sent or deleted from the store.
+	OnError func(msg ScheduledMessage, err error)
+}
+
+// Scheduler sends notifications at a later point in time through a Notifier, usually a Notify instance. Messages are
+// kept in memory and, if a ScheduleStore is configured, persisted, so that they can be rescheduled after a restart by
+// calling Start.
+//
+// A Scheduler is safe for concurrent use.
+type Scheduler struct {
+	notifier Notifier
+	opts     SchedulerOptions
+	now      func() time.Time
+
+	mu      sync.Mutex
+	pending map[string]*scheduledTimer
+}
+
+// scheduledTimer is a pending message together with the timer that sends it.
+type scheduledTimer struct {
+	msg   ScheduledMessage
+	timer *time.Timer
+}
+
+// NewScheduler returns a new Scheduler that sends messages through the given notifier.
+func NewScheduler(notifier Notifier, opts SchedulerOptions) *Scheduler {
+	return &Scheduler{
+		notifier: notifier,
+		opts:     opts,
+		now:      time.Now,
+		pending:  make(map[string]*scheduledTimer),
+	}
+}
+
+// newID returns a new random ID.
+func newID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("generate ID: %w", err)
+	}
+
+	return hex.EncodeToString(b[:]), nil
+}
+
+// Start loads all messages from the store and schedules them. Messages that are overdue, e.g. because the process was
+// not running at the time they were due, are sent right away. Start is a no-op if no store is configured.
+func (s *Scheduler) Start(ctx context.Context) error {
+	if s.opts.Store == nil {
+		return nil
+	}
+
+	messages, err := s.opts.Store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list scheduled messages: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, msg := range messages {
+		if _, ok := s.pending[msg.ID]; !ok {
+			s.schedule(msg)
+		}
+	}
+
+	return nil
+}
+
+// schedule arms the timer for the given message. The caller must hold the lock.
+func (s *Scheduler) schedule(msg ScheduledMessage) {
+	s.pending[msg.ID] = &scheduledTimer{
+		msg:   msg,
+		timer: time.AfterFunc(msg.At.Sub(s.now()), func() { s.fire(msg.ID) }),
+	}
+}
+
+// fire sends the message with the given ID, unless it was canceled in the meantime.
+func (s *Scheduler) fire(id string) {
+	s.mu.Lock()
+	pending, ok := s.pending[id]
+	delete(s.pending, id)
+	s.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	msg := pending.msg
+	ctx := msg.context()
+
+	err := s.notifier.Send(ctx, msg.Subject, msg.Message)
+	if err != nil {
+		err = fmt.Errorf("send scheduled message %s: %w", msg.ID, err)
+	}
+
+	// Messages are removed from the store even if they failed, to not send them again on every restart.
+	if s.opts.Store != nil {
+		if deleteErr := s.opts.Store.Delete(ctx, msg.ID); deleteErr != nil {
+			err = errors.Join(err, fmt.Errorf("delete scheduled message %s: %w", msg.ID, deleteErr))
+		}
+	}
+
+	if err != nil && s.opts.OnError != nil {
+		s.opts.OnError(msg, err)
+	}
+}
+
+// SendAt schedules the given subject and message to be sent at the given point in time and returns the ID of the
+// scheduled message. The severity and priority carried by the context are preserved; everything else about the context,
+// including its cancellation, only applies to scheduling the message, not to sending it.
+func (s *Scheduler) SendAt(ctx context.Context, at time.Time, subject, message string) (string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+
+	msg := ScheduledMessage{
+		ID:      id,
+		At:      at,
+		Subject: subject,
+		Message: message,
+	}
+	if severity, ok := SeverityFromContext(ctx); ok {
+		msg.Severity = &severity
+	}
+	if priority, ok := PriorityFromContext(ctx); ok {
+		msg.Priority = &priority
+	}
+
+	if s.opts.Store != nil {
+		if err = s.opts.Store.Save(ctx, msg); err != nil {
+			return "", fmt.Errorf("save scheduled message: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	s.schedule(msg)
+	s.mu.Unlock()
+
+	return id, nil
+}
+
+// SendAfter schedules the given subject and message to be sent after the given duration and returns the ID of the
+// scheduled message.
+func (s *Scheduler) SendAfter(ctx context.Context, delay time.Duration, subject, message string) (string, error) {
+	return s.SendAt(ctx, s.now().Add(delay), subject, message)
+}
+
+// Cancel cancels the scheduled message with the given ID. It returns ErrScheduledMessageNotFound if the message was
+// already sent or canceled.
+func (s *Scheduler) Cancel(ctx context.Context, id string) error {
+	s.mu.Lock()
+	pending, ok := s.pending[id]
+	if ok {
+		pending.timer.Stop()
+		delete(s.pending, id)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrScheduledMessageNotFound, id)
+	}
+
+	if s.opts.Store != nil {
+		if err := s.opts.Store.Delete(ctx, id); err != nil {
+			return fmt.Errorf("delete scheduled message: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Pending returns all messages that are scheduled but not sent yet, ordered by the time they are due.
+func (s *Scheduler) Pending() []ScheduledMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := make([]ScheduledMessage, 0, len(s.pending))
+	for _, pending := range s.pending {
+		messages = append(messages, pending.msg)
+	}
+	slices.SortFunc(messages, func(a, b ScheduledMessage) int {
+		return a.At.Compare(b.At)
+	})
+
+	return messages
+}
+
+// Stop stops all timers without sending or deleting the pending messages. Messages in the store are rescheduled by the
+// next call to Start.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, pending := range s.pending {
+		pending.timer.Stop()
+		delete(s.pending, id)
+	}
+}
+
+// FileScheduleStore is a ScheduleStore that keeps all scheduled messages in a single JSON file. It's meant for
+// low-volume use, e.g. reminders, since the whole file is rewritten on every change.
+type FileScheduleStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// Compile-time check to ensure FileScheduleStore implements ScheduleStore.
+var _ ScheduleStore = (*FileScheduleStore)(nil)
+
+// NewFileScheduleStore returns a new FileScheduleStore that uses the file at the given path. The file is created on the
+// first write.
+func NewFileScheduleStore(path string) *FileScheduleStore {
+	return &FileScheduleStore{path: path}
+}
+
+// read returns all messages in the file. The caller must hold the lock.
+func (f *FileScheduleStore) read() ([]ScheduledMessage, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []ScheduledMessage
+	if err = json.Unmarshal(data, &messages); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", f.path, err)
+	}
+
+	return messages, nil
+}
+
+// write atomically replaces the file with the given messages. The caller must hold the lock.
+func (f *FileScheduleStore) write(messages []ScheduledMessage) error {
+	data, err := json.Marshal(messages)
+	if err != nil {
+		return fmt.Errorf("encode scheduled messages: %w", err)
+	}
+
+	return writeFileAtomic(f.path, data)
+}
+
+// writeFileAtomic replaces the file at the given path with the given data, so that readers never see a partial write.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Save stores the given message, replacing any message with the same ID.
+func (f *FileScheduleStore) Save(_ context.Context, msg ScheduledMessage) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	messages, err := f.read()
+	if err != nil {
+		return err
+	}
+
+	messages = slices.DeleteFunc(messages, func(m ScheduledMessage) bool { return m.ID == msg.ID })
+
+	return f.write(append(messages, msg))
+}
+
+// Delete removes the message with the given ID.
+func (f *FileScheduleStore) Delete(_ context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	messages, err := f.read()
+	if err != nil {
+		return err
+	}
+
+	n := len(messages)
+	messages = slices.DeleteFunc(messages, func(m ScheduledMessage) bool { return m.ID == id })
+	if len(messages) == n {
+		return nil
+	}
+
+	return f.write(messages)
+}
+
+// List returns all stored messages.
+func (f *FileScheduleStore) List(_ context.Context) ([]ScheduledMessage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.read()
+}
+
+// scheduler returns the in-memory scheduler of the Notify instance, creating it on first use.
+func (n *Notify) scheduler() *Scheduler {
+	n.schedulerOnce.Do(func() {
+		n.sched = NewScheduler(n, SchedulerOptions{})
+	})
+
+	return n.sched
+}
+
+// SendAt schedules the given subject and message to be sent to all services at the given point in time. It returns an
+// ID that can be passed to CancelScheduled. Scheduled messages are kept in memory only; use a Scheduler with a
+// ScheduleStore to keep them across restarts.
+func (n *Notify) SendAt(ctx context.Context, at time.Time, subject, message string) (string, error) {
+	return n.scheduler().SendAt(ctx, at, subject, message)
+}
+
+// SendAfter schedules the given subject and message to be sent to all services after the given duration. It returns an
+// ID that can be passed to CancelScheduled.
+func (n *Notify) SendAfter(ctx context.Context, delay time.Duration, subject, message string) (string, error) {
+	return n.scheduler().SendAfter(ctx, delay, subject, message)
+}
+
+// CancelScheduled cancels the message with the given ID that was scheduled using SendAt or SendAfter.
+func (n *Notify) CancelScheduled(id string) error {
+	return n.scheduler().Cancel(context.Background(), id)
+}
+
+// SendAt schedules the given subject and message to be sent to all services at the given point in time.
+func SendAt(ctx context.Context, at time.Time, subject, message string) (string, error) {
+	return std.SendAt(ctx, at, subject, message)
+}
+
+// SendAfter schedules the given subject and message to be sent to all services after the given duration.
+func SendAfter(ctx context.Context, delay time.Duration, subject, message string) (string, error) {
+	return std.SendAfter(ctx, delay, subject, message)
+}
+
+// CancelScheduled cancels the message with the given ID that was scheduled using SendAt or SendAfter.
+func CancelScheduled(id string) error {
+	return std.CancelScheduled(id)
+}
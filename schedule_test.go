@@ -0,0 +1,128 @@
+package notify
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// sentMessage is a message received by a channelNotifier.
+type sentMessage struct {
+	subject  string
+	severity Severity
+}
+
+// channelNotifier is a Notifier that passes every message it's sent to a channel.
+type channelNotifier chan sentMessage
+
+func (c channelNotifier) Send(ctx context.Context, subject, _ string) error {
+	severity, _ := SeverityFromContext(ctx)
+	c <- sentMessage{subject: subject, severity: severity}
+
+	return nil
+}
+
+func TestScheduler_SendAfter(t *testing.T) {
+	t.Parallel()
+
+	sent := make(channelNotifier, 1)
+	scheduler := NewScheduler(sent, SchedulerOptions{})
+
+	ctx := WithSeverity(context.Background(), SeverityWarning)
+	id, err := scheduler.SendAfter(ctx, 10*time.Millisecond, "reminder", "message")
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+	require.Len(t, scheduler.Pending(), 1)
+
+	select {
+	case msg := <-sent:
+		require.Equal(t, sentMessage{subject: "reminder", severity: SeverityWarning}, msg)
+	case <-time.After(time.Second):
+		t.Fatal("scheduled message was not sent")
+	}
+
+	require.Empty(t, scheduler.Pending())
+	require.ErrorIs(t, scheduler.Cancel(context.Background(), id), ErrScheduledMessageNotFound)
+}
+
+func TestScheduler_Cancel(t *testing.T) {
+	t.Parallel()
+
+	sent := make(channelNotifier, 1)
+	scheduler := NewScheduler(sent, SchedulerOptions{})
+
+	id, err := scheduler.SendAfter(context.Background(), 20*time.Millisecond, "canceled", "message")
+	require.NoError(t, err)
+	require.NoError(t, scheduler.Cancel(context.Background(), id))
+
+	select {
+	case msg := <-sent:
+		t.Fatalf("canceled message was sent: %v", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestScheduler_Store(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := NewFileScheduleStore(filepath.Join(t.TempDir(), "scheduled.json"))
+
+	// Schedule a message and "restart" before it's due.
+	first := NewScheduler(make(channelNotifier, 1), SchedulerOptions{Store: store})
+	id, err := first.SendAt(WithSeverity(ctx, SeverityCritical), time.Now().Add(time.Hour), "maintenance", "message")
+	require.NoError(t, err)
+	first.Stop()
+	require.Empty(t, first.Pending())
+
+	// A message that became due while the process wasn't running.
+	require.NoError(t, store.Save(ctx, ScheduledMessage{ID: "overdue", At: time.Now().Add(-time.Minute), Subject: "overdue"}))
+
+	sent := make(channelNotifier, 1)
+	second := NewScheduler(sent, SchedulerOptions{Store: store})
+	require.NoError(t, second.Start(ctx))
+
+	select {
+	case msg := <-sent:
+		require.Equal(t, "overdue", msg.subject)
+	case <-time.After(time.Second):
+		t.Fatal("overdue message was not sent")
+	}
+
+	pending := second.Pending()
+	require.Len(t, pending, 1)
+	require.Equal(t, id, pending[0].ID)
+	require.Equal(t, SeverityCritical, *pending[0].Severity)
+
+	require.NoError(t, second.Cancel(ctx, id))
+
+	require.Eventually(t, func() bool {
+		stored, err := store.List(ctx)
+		return err == nil && len(stored) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestNotify_SendAfter(t *testing.T) {
+	t.Parallel()
+
+	sent := make(channelNotifier, 1)
+	n := NewWithServices(sent)
+
+	id, err := n.SendAfter(context.Background(), time.Hour, "later", "message")
+	require.NoError(t, err)
+	require.NoError(t, n.CancelScheduled(id))
+	require.ErrorIs(t, n.CancelScheduled(id), ErrScheduledMessageNotFound)
+
+	_, err = n.SendAt(context.Background(), time.Now(), "now", "message")
+	require.NoError(t, err)
+
+	select {
+	case msg := <-sent:
+		require.Equal(t, "now", msg.subject)
+	case <-time.After(time.Second):
+		t.Fatal("scheduled message was not sent")
+	}
+}
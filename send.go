@@ -16,12 +16,10 @@ func (n *Notify) send(ctx context.Context, subject, message string) error {
 		ctx = context.Background()
 	}
 
-	var eg errgroup.Group
-	for _, service := range n.notifiers {
-		if service == nil {
-			continue
-		}
+	severity, _ := SeverityFromContext(ctx)
 
+	var eg errgroup.Group
+	for _, service := range n.enabledServices(severity) {
 		eg.Go(func() error {
 			return service.Send(ctx, subject, message)
 		})
@@ -36,11 +34,26 @@ func (n *Notify) send(ctx context.Context, subject, message string) error {
 }
 
 // Send calls the underlying notification services to send the given subject and message to their respective endpoints.
+//
+// The notification is sent with the severity carried by the context, see WithSeverity, or with DefaultSeverity if there
+// is none. Services whose minimum severity is higher are skipped.
 func (n *Notify) Send(ctx context.Context, subject, message string) error {
 	return n.send(ctx, subject, message)
 }
 
+// SendWithSeverity calls the underlying notification services that accept the given severity to send the given subject
+// and message to their respective endpoints. The severity is passed on to the services through the context.
+func (n *Notify) SendWithSeverity(ctx context.Context, severity Severity, subject, message string) error {
+	return n.send(WithSeverity(ctx, severity), subject, message)
+}
+
 // Send calls the underlying notification services to send the given subject and message to their respective endpoints.
 func Send(ctx context.Context, subject, message string) error {
 	return std.Send(ctx, subject, message)
 }
+
+// SendWithSeverity calls the underlying notification services that accept the given severity to send the given subject
+// and message to their respective endpoints.
+func SendWithSeverity(ctx context.Context, severity Severity, subject, message string) error {
+	return std.SendWithSeverity(ctx, severity, subject, message)
+}
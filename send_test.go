@@ -3,8 +3,6 @@ package notify
 import (
 	"context"
 	"testing"
-
-	"github.com/nikoksr/notify/service/mail"
 )
 
 func TestNotifySend(t *testing.T) {
@@ -29,11 +27,11 @@ func TestNotifySend(t *testing.T) {
 		t.Errorf("Send() returned error: %v", err)
 	}
 
-	// This is not meant to test the mail service, but rather the general capability of the Send() function to catch
+	// This is not meant to test any particular service, but rather the general capability of the Send() function to catch
 	// errors.
-	n.UseServices(mail.New("", ""))
+	n.UseServices(newFailingNotifier())
 	if err := n.Send(ctx, "subject", "message"); err == nil {
-		t.Errorf("Send() invalid mail returned no error: %v", err)
+		t.Errorf("Send() failing service returned no error: %v", err)
 	}
 
 	// After disabling the Notifier, Send() should return silently.
@@ -47,8 +45,8 @@ func TestNotifySend(t *testing.T) {
 
 	// Smuggle in a nil service. This usually never happens, since UseServices filters out nil services. But, it's good
 	// to test anyway.
-	n.notifiers = make([]Notifier, 0)
-	n.notifiers = append(n.notifiers, nil)
+	n.services = make([]*serviceEntry, 0)
+	n.services = append(n.services, &serviceEntry{name: "nil"})
 
 	if err := n.Send(ctx, "subject", "message"); err != nil {
 		t.Errorf("Send() of disabled Notifier returned no error: %v", err)
@@ -69,12 +67,12 @@ func TestSendMany(t *testing.T) {
 	var services []Notifier
 
 	for range 10 {
-		services = append(services, mail.New("", ""))
+		services = append(services, newFailingNotifier())
 	}
 
 	n.UseServices(services...)
 
 	if err := n.Send(context.Background(), "subject", "message"); err == nil {
-		t.Errorf("Send() invalid mail returned no error: %v", err)
+		t.Errorf("Send() failing service returned no error: %v", err)
 	}
 }
@@ -3,12 +3,16 @@ package amazonses
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/ses"
 	"github.com/aws/aws-sdk-go-v2/service/ses/types"
+
+	"github.com/nikoksr/notify"
+	"github.com/nikoksr/notify/internal/htmltext"
 )
 
 //go:generate mockery --name=sesClient --output=. --case=underscore --inpackage
@@ -28,8 +32,23 @@ type AmazonSES struct {
 	client            sesClient
 	senderAddress     *string
 	receiverAddresses []string
+	usePlainText      bool
+	generatePlainText bool
 }
 
+// BodyType is used to specify the format of the body.
+type BodyType int
+
+const (
+	// PlainText is used to specify that the body is plain text.
+	PlainText BodyType = iota
+	// HTML is used to specify that the body is HTML.
+	HTML
+	// Alternative is used to specify that the body is HTML, which is sent together with a plain-text version that is
+	// generated from it.
+	Alternative
+)
+
 // New returns a new instance of a AmazonSES notification service.
 // You will need an Amazon Simple Email Service API access key and secret.
 // See https://aws.github.io/aws-sdk-go-v2/docs/getting-started/
@@ -58,8 +77,46 @@ func (a *AmazonSES) AddReceivers(addresses ...string) {
 	a.receiverAddresses = append(a.receiverAddresses, addresses...)
 }
 
+// BodyFormat can be used to specify the format of the body.
+// Default BodyType is HTML.
+func (a *AmazonSES) BodyFormat(format BodyType) {
+	switch format {
+	case PlainText:
+		a.usePlainText = true
+		a.generatePlainText = false
+	case HTML:
+		a.usePlainText = false
+		a.generatePlainText = false
+	case Alternative:
+		a.usePlainText = false
+		a.generatePlainText = true
+	default:
+		a.usePlainText = false
+		a.generatePlainText = false
+	}
+}
+
+// body returns the body of a mail with the given message. HTML messages get a plain-text alternative, either from the
+// given context or generated from the HTML if the body format is Alternative.
+func (a *AmazonSES) body(ctx context.Context, message string) *types.Body {
+	if a.usePlainText {
+		return &types.Body{Text: &types.Content{Data: aws.String(message)}}
+	}
+
+	body := &types.Body{Html: &types.Content{Data: aws.String(message)}}
+	if text, ok := notify.PlainTextFromContext(ctx); ok {
+		body.Text = &types.Content{Data: aws.String(text)}
+	} else if a.generatePlainText {
+		body.Text = &types.Content{Data: aws.String(htmltext.Convert(message))}
+	}
+
+	return body
+}
+
 // Send takes a message subject and a message body and sends them to all previously set chats. Message body supports
-// html as markup language.
+// html as markup language. HTML messages are sent as multipart/alternative together with the plain text carried by the
+// context, see notify.WithPlainText. Without it, the plain text is generated from the HTML if the body format is
+// Alternative.
 func (a AmazonSES) Send(ctx context.Context, subject, message string) error {
 	input := &ses.SendEmailInput{
 		Source: a.senderAddress,
@@ -67,14 +124,7 @@ func (a AmazonSES) Send(ctx context.Context, subject, message string) error {
 			ToAddresses: a.receiverAddresses,
 		},
 		Message: &types.Message{
-			Body: &types.Body{
-				Html: &types.Content{
-					Data: aws.String(message),
-				},
-				// Text: &types.Content{
-				//     Data:    aws.String(message),
-				// },
-			},
+			Body: a.body(ctx, message),
 			Subject: &types.Content{
 				Data: aws.String(subject),
 			},
@@ -83,7 +133,11 @@ func (a AmazonSES) Send(ctx context.Context, subject, message string) error {
 
 	_, err := a.client.SendEmail(ctx, input)
 	if err != nil {
-		return fmt.Errorf("send mail using Amazon SES service: %w", err)
+		return notify.NewReceiverError(
+			"amazonses",
+			strings.Join(a.receiverAddresses, ","),
+			fmt.Errorf("send mail using Amazon SES service: %w", err),
+		)
 	}
 
 	return nil
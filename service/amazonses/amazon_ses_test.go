@@ -7,8 +7,11 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ses"
+	"github.com/aws/aws-sdk-go-v2/service/ses/types"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/notify"
 )
 
 func TestAmazonSES_Send(t *testing.T) {
@@ -71,3 +74,82 @@ func TestAmazonSES_Send(t *testing.T) {
 		})
 	}
 }
+
+func TestAmazonSES_SendBody(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		format        BodyType
+		defaultFormat bool
+		ctx           context.Context
+		wantHTML      *string
+		wantText      *string
+	}{
+		{
+			name:          "Default",
+			defaultFormat: true,
+			ctx:           context.Background(),
+			wantHTML:      aws.String("<p>Disk <b>full</b></p>"),
+		},
+		{
+			name:     "HTML",
+			format:   HTML,
+			ctx:      context.Background(),
+			wantHTML: aws.String("<p>Disk <b>full</b></p>"),
+		},
+		{
+			name:     "HTML with plain text from context",
+			format:   HTML,
+			ctx:      notify.WithPlainText(context.Background(), "Disk is full"),
+			wantHTML: aws.String("<p>Disk <b>full</b></p>"),
+			wantText: aws.String("Disk is full"),
+		},
+		{
+			name:     "Alternative",
+			format:   Alternative,
+			ctx:      context.Background(),
+			wantHTML: aws.String("<p>Disk <b>full</b></p>"),
+			wantText: aws.String("Disk full"),
+		},
+		{
+			name:     "Plain text",
+			format:   PlainText,
+			ctx:      notify.WithPlainText(context.Background(), "Disk is full"),
+			wantText: aws.String("<p>Disk <b>full</b></p>"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockClient := new(mocksesClient)
+			mockClient.On("SendEmail", mock.Anything, mock.MatchedBy(func(input *ses.SendEmailInput) bool {
+				body := input.Message.Body
+				return equalContent(body.Html, tt.wantHTML) && equalContent(body.Text, tt.wantText)
+			})).Return(&ses.SendEmailOutput{}, nil)
+
+			s := &AmazonSES{
+				client:            mockClient,
+				senderAddress:     aws.String("sender@example.com"),
+				receiverAddresses: []string{"test@example.com"},
+			}
+			if !tt.defaultFormat {
+				s.BodyFormat(tt.format)
+			}
+
+			require.NoError(t, s.Send(tt.ctx, "Test Subject", "<p>Disk <b>full</b></p>"))
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+// equalContent reports whether the given content has the given data, or is nil if the data is nil.
+func equalContent(content *types.Content, data *string) bool {
+	if content == nil || data == nil {
+		return content == nil && data == nil
+	}
+
+	return aws.ToString(content.Data) == *data
+}
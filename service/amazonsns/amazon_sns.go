@@ -8,6 +8,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/sns"
+
+	"github.com/nikoksr/notify"
 )
 
 // snsSendMessageAPI Basic interface to send messages through SNS.
@@ -75,8 +77,25 @@ func (s AmazonSNS) Send(ctx context.Context, subject, message string) error {
 		// Send the message
 		_, err := s.sendMessageClient.SendMessage(ctx, input)
 		if err != nil {
-			return fmt.Errorf("send message using Amazon SNS to ARN TOPIC %q: %w", topic, err)
+			return newReceiverError(topic, fmt.Errorf("send message using Amazon SNS to ARN TOPIC %q: %w", topic, err))
 		}
 	}
 	return nil
 }
+
+// newReceiverError wraps the given error into a notify.ReceiverError and classifies it based on the error codes of the
+// Amazon SNS API. The HTTP status code and the error code are picked up by notify.NewReceiverError automatically.
+func newReceiverError(topic string, err error) *notify.ReceiverError {
+	rErr := notify.NewReceiverError("amazonsns", topic, err)
+
+	switch rErr.Code {
+	case "AuthorizationError", "InvalidClientTokenId", "ExpiredToken":
+		rErr.SetKind(notify.ErrAuthFailure)
+	case "NotFound", "EndpointDisabled":
+		rErr.SetKind(notify.ErrInvalidReceiver)
+	case "Throttled", "Throttling", "ThrottlingException":
+		rErr.SetKind(notify.ErrRateLimited)
+	}
+
+	return rErr
+}
@@ -10,6 +10,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/nikoksr/notify"
 )
 
 // Service allow you to configure Bark service.
@@ -17,6 +19,7 @@ type Service struct {
 	deviceKey  string
 	client     *http.Client
 	serverURLs []string
+	delivery   notify.DeliveryOptions
 }
 
 func defaultHTTPClient() *http.Client {
@@ -57,6 +60,12 @@ func (s *Service) AddReceivers(serverURLs ...string) {
 	}
 }
 
+// SetDeliveryOptions configures how the Send method delivers a message to multiple servers. By default, servers are
+// processed sequentially and delivery stops at the first error.
+func (s *Service) SetDeliveryOptions(opts notify.DeliveryOptions) {
+	s.delivery = opts
+}
+
 // NewWithServers returns a new instance of Bark service. You can use this service to send messages to bark. You can
 // specify the servers to send the messages to. By default, the service will use the default server
 // (https://api.day.app/) if you don't specify any servers.
@@ -93,6 +102,42 @@ type postData struct {
 	Icon      string `json:"icon,omitempty"`
 	Group     string `json:"group,omitempty"`
 	URL       string `json:"pushURL,omitempty"`
+	Level     string `json:"level,omitempty"`
+}
+
+// levelFromContext translates the notify.Priority carried by the given context into a bark interruption level. It
+// returns an empty string if the context carries no priority, which leaves the level up to the bark server.
+func levelFromContext(ctx context.Context) string {
+	priority, ok := notify.PriorityFromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	switch {
+	case priority <= notify.PriorityLow:
+		return "passive"
+	case priority == notify.PriorityDefault:
+		return "active"
+	case priority == notify.PriorityHigh:
+		return "timeSensitive"
+	}
+
+	return "critical"
+}
+
+// statusCodeError is returned when the bark server responded with an unexpected status code.
+type statusCodeError struct {
+	code int
+	body string
+}
+
+func (e statusCodeError) Error() string {
+	return fmt.Sprintf("bark returned status code %d: %s", e.code, e.body)
+}
+
+// StatusCode returns the HTTP status code of the response.
+func (e statusCodeError) StatusCode() int {
+	return e.code
 }
 
 func (s *Service) send(ctx context.Context, serverURL, subject, content string) error {
@@ -106,6 +151,7 @@ func (s *Service) send(ctx context.Context, serverURL, subject, content string)
 		Title:     subject,
 		Body:      content,
 		Sound:     "alarm.caf",
+		Level:     levelFromContext(ctx),
 	}
 
 	messageJSON, err := json.Marshal(message)
@@ -137,29 +183,30 @@ func (s *Service) send(ctx context.Context, serverURL, subject, content string)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bark returned status code %d: %s", resp.StatusCode, string(result))
+		return statusCodeError{code: resp.StatusCode, body: string(result)}
 	}
 
 	return nil
 }
 
-// Send takes a message subject and a message content and sends them to bark application.
+// Send takes a message subject and a message content and sends them to bark application. The notify.Priority carried by
+// the context, if any, is translated into the bark interruption level; notify.PriorityUrgent results in a critical
+// alert that ignores Do Not Disturb.
 func (s *Service) Send(ctx context.Context, subject, content string) error {
 	if s.client == nil {
 		return errors.New("client is nil")
 	}
 
-	for _, serverURL := range s.serverURLs {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			err := s.send(ctx, serverURL, subject, content)
-			if err != nil {
-				return fmt.Errorf("send message to bark server %q: %w", serverURL, err)
-			}
+	return notify.Deliver(ctx, s.delivery, s.serverURLs, func(ctx context.Context, serverURL string) error {
+		err := s.send(ctx, serverURL, subject, content)
+		if err != nil {
+			return notify.NewReceiverError(
+				"bark",
+				serverURL,
+				fmt.Errorf("send message to bark server %q: %w", serverURL, err),
+			)
 		}
-	}
 
-	return nil
+		return nil
+	})
 }
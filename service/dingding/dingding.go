@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/blinkbean/dingtalk"
+
+	"github.com/nikoksr/notify"
 )
 
 // Service encapsulates the DingTalk client.
@@ -39,7 +41,7 @@ func (s *Service) Send(ctx context.Context, subject, content string) error {
 		text := subject + "\n" + content
 		err := s.client.SendTextMessage(text)
 		if err != nil {
-			return fmt.Errorf("send message: %w", err)
+			return notify.NewReceiverError("dingding", "", fmt.Errorf("send message: %w", err))
 		}
 	}
 
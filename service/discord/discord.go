@@ -2,13 +2,18 @@ package discord
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/bwmarrin/discordgo"
+
+	"github.com/nikoksr/notify"
 )
 
 //go:generate mockery --name=discordSession --output=. --case=underscore --inpackage
 type discordSession interface {
+	User(userID string, options ...discordgo.RequestOption) (*discordgo.User, error)
 	ChannelMessageSend(channelID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error)
 }
 
@@ -19,6 +24,7 @@ var _ discordSession = new(discordgo.Session)
 type Discord struct {
 	client     discordSession
 	channelIDs []string
+	delivery   notify.DeliveryOptions
 }
 
 // New returns a new instance of a Discord notification service.
@@ -136,21 +142,64 @@ func (d *Discord) AddReceivers(channelIDs ...string) {
 	d.channelIDs = append(d.channelIDs, channelIDs...)
 }
 
+// SetDeliveryOptions configures how the Send method delivers a message to multiple channels. By default, channels are
+// processed sequentially and delivery stops at the first error.
+func (d *Discord) SetDeliveryOptions(opts notify.DeliveryOptions) {
+	d.delivery = opts
+}
+
 // Send takes a message subject and a message body and sends them to all previously set chats.
 func (d Discord) Send(ctx context.Context, subject, message string) error {
 	fullMessage := subject + "\n" + message // Treating subject as message title
 
-	for _, channelID := range d.channelIDs {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			_, err := d.client.ChannelMessageSend(channelID, fullMessage)
-			if err != nil {
-				return fmt.Errorf("send message to Discord channel %q: %w", channelID, err)
-			}
+	return notify.Deliver(ctx, d.delivery, d.channelIDs, func(_ context.Context, channelID string) error {
+		_, err := d.client.ChannelMessageSend(channelID, fullMessage)
+		if err != nil {
+			return newReceiverError(channelID, fmt.Errorf("send message to Discord channel %q: %w", channelID, err))
 		}
+
+		return nil
+	})
+}
+
+// Check verifies the authentication token by looking up the current user through the Discord REST API. It implements
+// the notify.Checker interface.
+func (d Discord) Check(ctx context.Context) error {
+	if _, err := d.client.User("@me", discordgo.WithContext(ctx)); err != nil {
+		return newReceiverError("", fmt.Errorf("get current user: %w", err))
 	}
 
 	return nil
 }
+
+// newReceiverError wraps the given error into a notify.ReceiverError and classifies it based on the REST errors
+// returned by discordgo.
+func newReceiverError(channelID string, err error) *notify.ReceiverError {
+	rErr := notify.NewReceiverError("discord", channelID, err)
+
+	var rateLimitErr *discordgo.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return rErr.SetKind(notify.ErrRateLimited)
+	}
+
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) {
+		return rErr
+	}
+
+	if restErr.Message != nil && restErr.Message.Code != 0 {
+		rErr.Code = strconv.Itoa(restErr.Message.Code)
+
+		switch restErr.Message.Code {
+		case discordgo.ErrCodeUnknownChannel, discordgo.ErrCodeMissingAccess, discordgo.ErrCodeCannotSendMessagesToThisUser:
+			rErr.SetKind(notify.ErrInvalidReceiver)
+		case discordgo.ErrCodeRequestEntityTooLarge:
+			rErr.SetKind(notify.ErrPayloadTooLarge)
+		}
+	}
+	if restErr.Response != nil {
+		rErr.SetStatusCode(restErr.Response.StatusCode)
+	}
+
+	return rErr
+}
@@ -122,3 +122,84 @@ func (_c *mockdiscordSession_ChannelMessageSend_Call) RunAndReturn(run func(chan
 	_c.Call.Return(run)
 	return _c
 }
+
+// User provides a mock function for the type mockdiscordSession
+func (_mock *mockdiscordSession) User(userID string, options ...discordgo.RequestOption) (*discordgo.User, error) {
+	// discordgo.RequestOption
+	_va := make([]interface{}, len(options))
+	for _i := range options {
+		_va[_i] = options[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, userID)
+	_ca = append(_ca, _va...)
+	ret := _mock.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for User")
+	}
+
+	var r0 *discordgo.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string, ...discordgo.RequestOption) (*discordgo.User, error)); ok {
+		return returnFunc(userID, options...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, ...discordgo.RequestOption) *discordgo.User); ok {
+		r0 = returnFunc(userID, options...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*discordgo.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, ...discordgo.RequestOption) error); ok {
+		r1 = returnFunc(userID, options...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockdiscordSession_User_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'User'
+type mockdiscordSession_User_Call struct {
+	*mock.Call
+}
+
+// User is a helper method to define mock.On call
+//   - userID string
+//   - options ...discordgo.RequestOption
+func (_e *mockdiscordSession_Expecter) User(userID interface{}, options ...interface{}) *mockdiscordSession_User_Call {
+	return &mockdiscordSession_User_Call{Call: _e.mock.On("User",
+		append([]interface{}{userID}, options...)...)}
+}
+
+func (_c *mockdiscordSession_User_Call) Run(run func(userID string, options ...discordgo.RequestOption)) *mockdiscordSession_User_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 []discordgo.RequestOption
+		variadicArgs := make([]discordgo.RequestOption, len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(discordgo.RequestOption)
+			}
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *mockdiscordSession_User_Call) Return(user *discordgo.User, err error) *mockdiscordSession_User_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *mockdiscordSession_User_Call) RunAndReturn(run func(userID string, options ...discordgo.RequestOption) (*discordgo.User, error)) *mockdiscordSession_User_Call {
+	_c.Call.Return(run)
+	return _c
+}
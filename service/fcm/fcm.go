@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"firebase.google.com/go/v4/messaging"
 	"github.com/appleboy/go-fcm"
@@ -92,12 +93,60 @@ func (s *Service) AddReceivers(deviceTokens ...string) {
 	s.deviceTokens = append(s.deviceTokens, deviceTokens...)
 }
 
-// Send takes a message subject and a message body and sends them to all previously set devices.
+// platformConfigs translates the notify.Priority carried by the given context into the native priorities of Android
+// and APNs. It returns nil configs if the context carries no priority.
+func platformConfigs(ctx context.Context) (*messaging.AndroidConfig, *messaging.APNSConfig) {
+	priority, ok := notify.PriorityFromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	// Android only knows normal and high delivery priority; the notification priority is more fine-grained. APNs uses
+	// 5 for power-saving and 10 for immediate delivery, and interruption levels to break through Focus modes.
+	androidPriority, apnsPriority := "high", "10"
+	var notificationPriority messaging.AndroidNotificationPriority
+	var interruptionLevel string
+
+	switch {
+	case priority <= notify.PriorityMin:
+		androidPriority, apnsPriority = "normal", "5"
+		notificationPriority, interruptionLevel = messaging.PriorityMin, "passive"
+	case priority == notify.PriorityLow:
+		androidPriority, apnsPriority = "normal", "5"
+		notificationPriority, interruptionLevel = messaging.PriorityLow, "passive"
+	case priority == notify.PriorityDefault:
+		notificationPriority, interruptionLevel = messaging.PriorityDefault, "active"
+	case priority == notify.PriorityHigh:
+		notificationPriority, interruptionLevel = messaging.PriorityHigh, "time-sensitive"
+	default:
+		notificationPriority, interruptionLevel = messaging.PriorityMax, "time-sensitive"
+	}
+
+	android := &messaging.AndroidConfig{
+		Priority:     androidPriority,
+		Notification: &messaging.AndroidNotification{Priority: notificationPriority},
+	}
+	apns := &messaging.APNSConfig{
+		Headers: map[string]string{"apns-priority": apnsPriority},
+		Payload: &messaging.APNSPayload{
+			Aps: &messaging.Aps{
+				CustomData: map[string]any{"interruption-level": interruptionLevel},
+			},
+		},
+	}
+
+	return android, apns
+}
+
+// Send takes a message subject and a message body and sends them to all previously set devices. The notify.Priority
+// carried by the context, if any, is translated into the Android and APNs priorities.
 func (s *Service) Send(ctx context.Context, subject, message string) error {
 	if len(s.deviceTokens) == 0 {
 		return errors.New("no device tokens set")
 	}
 
+	android, apns := platformConfigs(ctx)
+
 	if len(s.deviceTokens) == 1 {
 		msg := &messaging.Message{
 			Token: s.deviceTokens[0],
@@ -105,11 +154,16 @@ func (s *Service) Send(ctx context.Context, subject, message string) error {
 				Title: subject,
 				Body:  message,
 			},
+			Android: android,
+			APNS:    apns,
 		}
 
 		_, err := s.client.Send(ctx, msg)
 		if err != nil {
-			return fmt.Errorf("send message to FCM device with token %q: %w", s.deviceTokens[0], err)
+			return newReceiverError(
+				s.deviceTokens[0],
+				fmt.Errorf("send message to FCM device with token %q: %w", s.deviceTokens[0], err),
+			)
 		}
 	} else {
 		msg := &messaging.MulticastMessage{
@@ -118,13 +172,37 @@ func (s *Service) Send(ctx context.Context, subject, message string) error {
 				Title: subject,
 				Body:  message,
 			},
+			Android: android,
+			APNS:    apns,
 		}
 
 		_, err := s.client.SendMulticast(ctx, msg)
 		if err != nil {
-			return fmt.Errorf("send multicast message to FCM devices: %w", err)
+			return newReceiverError(
+				strings.Join(s.deviceTokens, ","),
+				fmt.Errorf("send multicast message to FCM devices: %w", err),
+			)
 		}
 	}
 
 	return nil
 }
+
+// newReceiverError wraps the given error into a notify.ReceiverError and classifies it using the error helpers of the
+// Firebase messaging package.
+func newReceiverError(deviceTokens string, err error) *notify.ReceiverError {
+	rErr := notify.NewReceiverError("fcm", deviceTokens, err)
+
+	switch {
+	case messaging.IsUnregistered(err), messaging.IsSenderIDMismatch(err):
+		rErr.SetKind(notify.ErrInvalidReceiver)
+	case messaging.IsThirdPartyAuthError(err):
+		rErr.SetKind(notify.ErrAuthFailure)
+	case messaging.IsQuotaExceeded(err):
+		rErr.SetKind(notify.ErrRateLimited)
+	case messaging.IsUnavailable(err), messaging.IsInternal(err):
+		rErr.Retryable = true
+	}
+
+	return rErr
+}
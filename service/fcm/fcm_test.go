@@ -8,6 +8,8 @@ import (
 	"firebase.google.com/go/v4/messaging"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/notify"
 )
 
 func TestService_Send(t *testing.T) {
@@ -91,3 +93,35 @@ func TestService_Send(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Send_Priority(t *testing.T) {
+	t.Parallel()
+
+	mockClient := new(mockfcmClient)
+	mockClient.
+		On("Send", mock.Anything, mock.MatchedBy(func(msg *messaging.Message) bool {
+			return msg.Android.Priority == "high" &&
+				msg.Android.Notification.Priority == messaging.PriorityMax &&
+				msg.APNS.Headers["apns-priority"] == "10" &&
+				msg.APNS.Payload.Aps.CustomData["interruption-level"] == "time-sensitive"
+		})).
+		Return(&messaging.BatchResponse{}, nil).
+		Once()
+	mockClient.
+		On("Send", mock.Anything, mock.MatchedBy(func(msg *messaging.Message) bool {
+			return msg.Android == nil && msg.APNS == nil
+		})).
+		Return(&messaging.BatchResponse{}, nil).
+		Once()
+
+	s := &Service{
+		client:       mockClient,
+		deviceTokens: []string{"token1"},
+	}
+
+	ctx := notify.WithPriority(context.Background(), notify.PriorityUrgent)
+	require.NoError(t, s.Send(ctx, "Test Subject", "Test Message"))
+	require.NoError(t, s.Send(context.Background(), "Test Subject", "Test Message"))
+
+	mockClient.AssertExpectations(t)
+}
@@ -2,11 +2,14 @@ package googlechat
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"google.golang.org/api/chat/v1"
 	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
+
+	"github.com/nikoksr/notify"
 )
 
 type spacesMessageCreator interface {
@@ -100,9 +103,22 @@ func (s *Service) Send(ctx context.Context, subject, message string) error {
 			return ctx.Err()
 		default:
 			if _, err := s.messageCreator.Create(parent, msg).Do(); err != nil {
-				return fmt.Errorf("send message to the google chat space %q: %w", space, err)
+				return newReceiverError(space, fmt.Errorf("send message to the google chat space %q: %w", space, err))
 			}
 		}
 	}
 	return nil
 }
+
+// newReceiverError wraps the given error into a notify.ReceiverError and classifies it based on the HTTP status code
+// returned by the Google Chat API.
+func newReceiverError(space string, err error) *notify.ReceiverError {
+	rErr := notify.NewReceiverError("googlechat", space, err)
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		rErr.SetStatusCode(apiErr.Code)
+	}
+
+	return rErr
+}
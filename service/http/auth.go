@@ -0,0 +1,157 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// defaultSigV4Service is the AWS service that SigV4Auth signs requests for by default, which is API Gateway.
+const defaultSigV4Service = "execute-api"
+
+type (
+	// Authenticator adds credentials to requests. It's called with the final request, right before it's signed by
+	// the Signer of the webhook, if any, and the payload that forms its body. Tokens and credentials are fetched using
+	// the context of the request, which carries the client of the webhook as oauth2.HTTPClient, so that the
+	// TransportOptions of the webhook apply to them as well.
+	Authenticator interface {
+		Authenticate(req *http.Request, payload []byte) error
+	}
+
+	// BasicAuth is an Authenticator that uses HTTP basic authentication.
+	BasicAuth struct {
+		Username string
+		Password string
+	}
+
+	// OAuth2Auth is an Authenticator that sets OAuth2 bearer tokens. Tokens are cached until shortly before they
+	// expire.
+	OAuth2Auth struct {
+		fetch func(ctx context.Context) (*oauth2.Token, error)
+
+		mu    sync.Mutex
+		token *oauth2.Token
+	}
+
+	// SigV4Auth is an Authenticator that signs requests using AWS Signature Version 4, e.g. for API Gateway endpoints
+	// that use IAM authorization.
+	SigV4Auth struct {
+		credentials aws.CredentialsProvider
+		region      string
+		service     string
+		signer      *v4.Signer
+		now         func() time.Time
+	}
+)
+
+// Compile-time checks to ensure the authenticators implement Authenticator.
+var (
+	_ Authenticator = BasicAuth{}
+	_ Authenticator = (*OAuth2Auth)(nil)
+	_ Authenticator = (*SigV4Auth)(nil)
+)
+
+// Authenticate sets the Authorization header of the given request.
+func (a BasicAuth) Authenticate(req *http.Request, _ []byte) error {
+	req.SetBasicAuth(a.Username, a.Password)
+
+	return nil
+}
+
+// NewOAuth2Auth returns a new OAuth2Auth that uses the given token source. The tokens of the source are cached. Token
+// sources fetch tokens on their own, so the context of the request and the client of the webhook don't apply to them;
+// use NewClientCredentialsConfigAuth for client credentials.
+func NewOAuth2Auth(source oauth2.TokenSource) *OAuth2Auth {
+	return &OAuth2Auth{fetch: func(context.Context) (*oauth2.Token, error) { return source.Token() }}
+}
+
+// NewClientCredentialsAuth returns a new OAuth2Auth that obtains tokens from the given token URL using the OAuth2 client
+// credentials flow. Use NewClientCredentialsConfigAuth for further options, e.g. endpoint parameters.
+func NewClientCredentialsAuth(clientID, clientSecret, tokenURL string, scopes ...string) *OAuth2Auth {
+	return NewClientCredentialsConfigAuth(&clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     tokenURL,
+		Scopes:       scopes,
+	})
+}
+
+// NewClientCredentialsConfigAuth returns a new OAuth2Auth that obtains tokens using the given client credentials
+// configuration. Tokens are fetched with the context of the request and the client of the webhook.
+func NewClientCredentialsConfigAuth(cfg *clientcredentials.Config) *OAuth2Auth {
+	return &OAuth2Auth{fetch: cfg.Token}
+}
+
+// Authenticate sets the Authorization header of the given request to a valid bearer token, fetching a new one if the
+// cached token expired.
+func (a *OAuth2Auth) Authenticate(req *http.Request, _ []byte) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.token.Valid() {
+		token, err := a.fetch(req.Context())
+		if err != nil {
+			return fmt.Errorf("fetch oauth2 token: %w", err)
+		}
+		a.token = token
+	}
+
+	a.token.SetAuthHeader(req)
+
+	return nil
+}
+
+// withAuthClient returns a copy of the given context that makes authenticators fetch credentials, e.g. OAuth2 tokens,
+// with the given client.
+func withAuthClient(ctx context.Context, client *http.Client) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, client)
+}
+
+// NewSigV4Auth returns a new SigV4Auth that signs requests for the given AWS region with the given credentials, e.g.
+// the Credentials of an aws.Config. The credentials are cached. Requests are signed for API Gateway unless a different
+// service is set using SetService.
+func NewSigV4Auth(credentials aws.CredentialsProvider, region string) *SigV4Auth {
+	if _, ok := credentials.(*aws.CredentialsCache); !ok {
+		credentials = aws.NewCredentialsCache(credentials)
+	}
+
+	return &SigV4Auth{
+		credentials: credentials,
+		region:      region,
+		service:     defaultSigV4Service,
+		signer:      v4.NewSigner(),
+		now:         time.Now,
+	}
+}
+
+// SetService sets the name of the AWS service requests are signed for, e.g. "lambda" for Lambda function URLs.
+func (a *SigV4Auth) SetService(service string) {
+	a.service = service
+}
+
+// Authenticate signs the given request. Pre-send hooks must not modify the request afterwards, as that would invalidate
+// the signature.
+func (a *SigV4Auth) Authenticate(req *http.Request, payload []byte) error {
+	credentials, err := a.credentials.Retrieve(req.Context())
+	if err != nil {
+		return fmt.Errorf("retrieve aws credentials: %w", err)
+	}
+
+	hash := sha256.Sum256(payload)
+	payloadHash := hex.EncodeToString(hash[:])
+
+	if err = a.signer.SignHTTP(req.Context(), credentials, req, payloadHash, a.service, a.region, a.now()); err != nil {
+		return fmt.Errorf("sign request: %w", err)
+	}
+
+	return nil
+}
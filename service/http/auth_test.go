@@ -0,0 +1,191 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/notify"
+)
+
+// newAuthService returns a service with a single webhook that uses the given authenticator, and a channel that receives
+// the requests of the webhook.
+func newAuthService(t *testing.T, auth Authenticator) (*Service, *Webhook, <-chan *http.Request) {
+	t.Helper()
+
+	requests := make(chan *http.Request, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		requests <- r
+	}))
+	t.Cleanup(server.Close)
+
+	webhook := newWebhook(server.URL)
+	webhook.Auth = auth
+
+	service := New()
+	service.AddReceivers(webhook)
+
+	return service, webhook, requests
+}
+
+func TestBasicAuth(t *testing.T) {
+	t.Parallel()
+
+	service, webhook, requests := newAuthService(t, BasicAuth{Username: "user", Password: "pass"})
+	require.NoError(t, service.Send(context.Background(), "subject", "message"))
+
+	username, password, ok := (<-requests).BasicAuth()
+	require.True(t, ok)
+	require.Equal(t, "user", username)
+	require.Equal(t, "pass", password)
+	require.Empty(t, webhook.Header.Get("Authorization"), "webhook header must not be modified")
+}
+
+func TestClientCredentialsAuth(t *testing.T) {
+	t.Parallel()
+
+	var tokenRequests atomic.Int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests.Add(1)
+
+		clientID, clientSecret, _ := r.BasicAuth()
+		if clientID != "client" || clientSecret != "secret" || r.FormValue("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"access_token": "token-`+r.FormValue("scope")+`", "token_type": "Bearer", "expires_in": 3600}`)
+	}))
+	defer tokenServer.Close()
+
+	auth := NewClientCredentialsAuth("client", "secret", tokenServer.URL, "notify")
+	service, _, requests := newAuthService(t, auth)
+
+	for range 3 {
+		require.NoError(t, service.Send(context.Background(), "subject", "message"))
+		require.Equal(t, "Bearer token-notify", (<-requests).Header.Get("Authorization"))
+	}
+
+	// The token is cached until it expires.
+	require.Equal(t, int32(1), tokenRequests.Load())
+
+	// Failing token requests fail the send.
+	service, _, _ = newAuthService(t, NewClientCredentialsAuth("client", "wrong", tokenServer.URL))
+	require.ErrorContains(t, service.Send(context.Background(), "subject", "message"), "fetch oauth2 token")
+}
+
+func TestSigV4Auth(t *testing.T) {
+	t.Parallel()
+
+	auth := NewSigV4Auth(credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""), "eu-central-1")
+	auth.now = func() time.Time { return time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC) }
+
+	service, _, requests := newAuthService(t, auth)
+	require.NoError(t, service.Send(context.Background(), "subject", "message"))
+
+	req := <-requests
+	require.Equal(t, "20240306T120000Z", req.Header.Get("X-Amz-Date"))
+	require.True(t, strings.HasPrefix(req.Header.Get("Authorization"),
+		"AWS4-HMAC-SHA256 Credential=AKID/20240306/eu-central-1/execute-api/aws4_request, SignedHeaders="))
+	require.Contains(t, req.Header.Get("Authorization"), "Signature=")
+
+	auth.SetService("lambda")
+	require.NoError(t, service.Send(context.Background(), "subject", "message"))
+	require.Contains(t, (<-requests).Header.Get("Authorization"), "/eu-central-1/lambda/aws4_request")
+}
+
+func TestSigV4Auth_CredentialsError(t *testing.T) {
+	t.Parallel()
+
+	provider := aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{}, errors.New("no credentials")
+	})
+
+	service, _, _ := newAuthService(t, NewSigV4Auth(provider, "eu-central-1"))
+	require.ErrorContains(t, service.Send(context.Background(), "subject", "message"), "retrieve aws credentials")
+}
+
+func TestService_Check_Auth(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	webhook := newWebhook(server.URL)
+	service := New()
+	service.AddReceivers(webhook)
+	require.ErrorIs(t, service.Check(context.Background()), notify.ErrAuthFailure)
+
+	webhook.Auth = BasicAuth{Username: "user", Password: "pass"}
+	require.NoError(t, service.Check(context.Background()))
+
+	webhook.Auth = BasicAuth{Username: "user", Password: "wrong"}
+	require.ErrorIs(t, service.Check(context.Background()), notify.ErrAuthFailure)
+}
+
+// countingTransport counts the requests it forwards to http.DefaultTransport.
+type countingTransport struct {
+	requests atomic.Int32
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestClientCredentialsAuth_RequestContext(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("scope") == "slow" {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"access_token": "token", "token_type": "Bearer", "expires_in": 3600}`)
+	}))
+	defer tokenServer.Close()
+
+	// Tokens are fetched with the client of the webhook.
+	service, _, requests := newAuthService(t, NewClientCredentialsAuth("client", "secret", tokenServer.URL))
+	transport := &countingTransport{}
+	service.WithClient(&http.Client{Transport: transport})
+
+	require.NoError(t, service.Send(context.Background(), "subject", "message"))
+	require.Equal(t, "Bearer token", (<-requests).Header.Get("Authorization"))
+	require.Equal(t, int32(2), transport.requests.Load(), "token and webhook requests must use the webhook client")
+
+	// Fetching tokens is canceled together with the request.
+	service, _, _ = newAuthService(t, NewClientCredentialsAuth("client", "secret", tokenServer.URL, "slow"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	require.ErrorIs(t, service.Send(ctx, "subject", "message"), context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"cmp"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// ContentTypeCloudEvents is the content type of CloudEvents in structured content mode.
+	ContentTypeCloudEvents = "application/cloudevents+json; charset=utf-8"
+
+	// DefaultCloudEventsType is the type of the CloudEvents sent by the service, unless configured otherwise.
+	DefaultCloudEventsType = "com.github.nikoksr.notify.message"
+
+	// cloudEventsSpecVersion is the version of the CloudEvents specification the events conform to.
+	cloudEventsSpecVersion = "1.0"
+)
+
+// CloudEventsMode is the content mode CloudEvents are sent in.
+type CloudEventsMode int
+
+// These are the supported content modes.
+const (
+	// CloudEventsStructured sends the event as JSON document, with the payload of the webhook as its data.
+	CloudEventsStructured CloudEventsMode = iota
+
+	// CloudEventsBinary sends the payload of the webhook unchanged, with the event attributes as ce-* headers.
+	CloudEventsBinary
+)
+
+// CloudEventsOptions make a Webhook send its payload as CloudEvent 1.0. Every event gets a random ID and the current
+// time.
+//
+// See https://github.com/cloudevents/spec/blob/v1.0.2/cloudevents/spec.md.
+type CloudEventsOptions struct {
+	// Mode is the content mode. Defaults to CloudEventsStructured.
+	Mode CloudEventsMode
+
+	// Source identifies the context in which the events happen, e.g. "/ci/nightly-build". It's required.
+	Source string
+
+	// Type is the type of the events. Defaults to DefaultCloudEventsType.
+	Type string
+
+	// Extensions are additional attributes that are added to every event. Their names must consist of lowercase
+	// letters and digits only.
+	Extensions map[string]string
+}
+
+// newEventID returns a random ID for a CloudEvent.
+func newEventID() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate event id: %w", err)
+	}
+
+	return hex.EncodeToString(raw), nil
+}
+
+// attributes returns the context attributes of a new event that occurred at the given time.
+func (o *CloudEventsOptions) attributes(now time.Time) (map[string]string, error) {
+	if o.Source == "" {
+		return nil, errors.New("cloudevents source is required")
+	}
+
+	id, err := newEventID()
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := make(map[string]string, len(o.Extensions)+5)
+	for name, value := range o.Extensions {
+		attributes[name] = value
+	}
+	attributes["specversion"] = cloudEventsSpecVersion
+	attributes["id"] = id
+	attributes["source"] = o.Source
+	attributes["type"] = cmp.Or(o.Type, DefaultCloudEventsType)
+	attributes["time"] = now.UTC().Format(time.RFC3339Nano)
+
+	return attributes, nil
+}
+
+// isJSONContentType reports whether the given content type denotes JSON data.
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// encode wraps the given payload of the given content type in a new CloudEvent that occurred at the given time. It
+// returns the body of the request and the headers that override those of the webhook: the event attributes in binary
+// content mode, or the content type in structured content mode.
+func (o *CloudEventsOptions) encode(contentType string, payload []byte, now time.Time) ([]byte, http.Header, error) {
+	attributes, err := o.attributes(now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if o.Mode == CloudEventsBinary {
+		header := make(http.Header, len(attributes))
+		for name, value := range attributes {
+			header.Set("ce-"+name, value)
+		}
+
+		return payload, header, nil
+	}
+
+	event := make(map[string]any, len(attributes)+2)
+	for name, value := range attributes {
+		event[name] = value
+	}
+	event["datacontenttype"] = contentType
+
+	switch {
+	case isJSONContentType(contentType):
+		event["data"] = json.RawMessage(payload)
+	case strings.HasPrefix(contentType, "text/"):
+		event["data"] = string(payload)
+	default:
+		event["data_base64"] = payload // Encoded as base64 by encoding/json.
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshal cloudevent: %w", err)
+	}
+
+	return body, http.Header{"Content-Type": {ContentTypeCloudEvents}}, nil
+}
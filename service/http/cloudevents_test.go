@@ -0,0 +1,117 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type cloudEventsRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newCloudEventsService returns a service with a single webhook that sends CloudEvents with the given options, and a
+// channel that receives the requests of the webhook.
+func newCloudEventsService(t *testing.T, opts *CloudEventsOptions) (*Service, *Webhook, <-chan cloudEventsRequest) {
+	t.Helper()
+
+	requests := make(chan cloudEventsRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- cloudEventsRequest{header: r.Header, body: body}
+	}))
+	t.Cleanup(server.Close)
+
+	webhook := newWebhook(server.URL)
+	webhook.CloudEvents = opts
+
+	service := New()
+	service.AddReceivers(webhook)
+	service.now = func() time.Time { return testEventTime }
+
+	return service, webhook, requests
+}
+
+// testEventTime is the time of the events sent by the service returned by newCloudEventsService.
+var testEventTime = time.Date(2024, 3, 6, 12, 0, 0, 500, time.FixedZone("CET", 3600))
+
+func TestCloudEvents_Structured(t *testing.T) {
+	t.Parallel()
+
+	service, _, requests := newCloudEventsService(t, &CloudEventsOptions{
+		Source:     "/ci/nightly",
+		Extensions: map[string]string{"severity": "high"},
+	})
+	require.NoError(t, service.Send(context.Background(), "subject", "message"))
+
+	req := <-requests
+	require.Equal(t, ContentTypeCloudEvents, req.header.Get("Content-Type"))
+
+	var event map[string]any
+	require.NoError(t, json.Unmarshal(req.body, &event))
+	require.Equal(t, "1.0", event["specversion"])
+	require.Equal(t, "/ci/nightly", event["source"])
+	require.Equal(t, DefaultCloudEventsType, event["type"])
+	require.Equal(t, "high", event["severity"])
+	require.Equal(t, "application/json; charset=utf-8", event["datacontenttype"])
+	require.Equal(t, map[string]any{"subject": "subject", "message": "message"}, event["data"])
+	require.Len(t, event["id"], 32)
+	require.Equal(t, "2024-03-06T11:00:00.0000005Z", event["time"])
+
+	// Every event gets a new ID.
+	require.NoError(t, service.Send(context.Background(), "subject", "message"))
+
+	var next map[string]any
+	require.NoError(t, json.Unmarshal((<-requests).body, &next))
+	require.NotEqual(t, event["id"], next["id"])
+}
+
+func TestCloudEvents_StructuredData(t *testing.T) {
+	t.Parallel()
+
+	opts := &CloudEventsOptions{Source: "/test"}
+
+	body, _, err := opts.encode("text/plain; charset=utf-8", []byte("hello"), testEventTime)
+	require.NoError(t, err)
+	require.Contains(t, string(body), `"data":"hello"`)
+
+	body, _, err = opts.encode("application/octet-stream", []byte{0xff, 0x00}, testEventTime)
+	require.NoError(t, err)
+	require.Contains(t, string(body), `"data_base64":"/wA="`)
+	require.NotContains(t, string(body), `"data":`)
+}
+
+func TestCloudEvents_Binary(t *testing.T) {
+	t.Parallel()
+
+	service, webhook, requests := newCloudEventsService(t, &CloudEventsOptions{
+		Mode:   CloudEventsBinary,
+		Source: "/ci/nightly",
+		Type:   "com.example.build.failed",
+	})
+	require.NoError(t, service.Send(context.Background(), "subject", "message"))
+
+	req := <-requests
+	require.Equal(t, "application/json; charset=utf-8", req.header.Get("Content-Type"))
+	require.JSONEq(t, `{"subject": "subject", "message": "message"}`, string(req.body))
+	require.Equal(t, "1.0", req.header.Get("ce-specversion"))
+	require.Equal(t, "/ci/nightly", req.header.Get("ce-source"))
+	require.Equal(t, "com.example.build.failed", req.header.Get("ce-type"))
+	require.Len(t, req.header.Get("ce-id"), 32)
+	require.Equal(t, "2024-03-06T11:00:00.0000005Z", req.header.Get("ce-time"))
+	require.Empty(t, webhook.Header.Get("ce-id"), "webhook header must not be modified")
+}
+
+func TestCloudEvents_MissingSource(t *testing.T) {
+	t.Parallel()
+
+	service, _, _ := newCloudEventsService(t, &CloudEventsOptions{})
+	require.ErrorContains(t, service.Send(context.Background(), "subject", "message"), "cloudevents source is required")
+}
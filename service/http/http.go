@@ -8,7 +8,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/nikoksr/notify"
 )
@@ -31,23 +34,54 @@ type (
 	// Webhook represents a single webhook receiver. It contains all the information needed to send a valid request to
 	// the receiver. The BuildPayload function is used to build the payload that will be sent to the receiver from the
 	// given subject and message.
+	//
+	// If PriorityHeader is set, the notify.Priority carried by the context of a send is passed in the header with this
+	// name, as a number from 1 (min) to 5 (urgent). This is the format used by ntfy, which expects the header
+	// "Priority".
+	//
+	// If Signer is set, every request is signed with it, e.g. using a StandardWebhooksSigner or a HubSigner, so that the
+	// receiver can verify its authenticity. If Auth is set, it adds credentials to every request, e.g. using BasicAuth,
+	// OAuth2Auth, or SigV4Auth.
+	//
+	// If ValidateResponse is set, it decides which responses count as success, e.g. using ExpectStatus and
+	// ExpectJSONField. Otherwise, every response with a 2xx status code does. Rejected responses are reported as
+	// *ResponseError.
+	//
+	// If CloudEvents is set, every notification is sent as CloudEvent, e.g. for event routers like Knative or Argo
+	// Events. The payload built by BuildPayload becomes the data of the event.
+	//
+	// If Transport is set, the requests are sent with a client that is configured by it, e.g. with client certificates
+	// for mutual TLS, a custom CA bundle, a proxy, or a timeout that differs from the other webhooks.
 	Webhook struct {
-		ContentType  string
-		Header       http.Header
-		Method       string
-		URL          string
-		BuildPayload BuildPayloadFn
+		ContentType      string
+		Header           http.Header
+		Method           string
+		URL              string
+		BuildPayload     BuildPayloadFn
+		PriorityHeader   string
+		Signer           Signer
+		Auth             Authenticator
+		ValidateResponse ResponseValidatorFn
+		CloudEvents      *CloudEventsOptions
+		Transport        *TransportOptions
 	}
 
 	// Service is the main struct of this package. It contains all the information needed to send notifications to a
-	// list of receivers. The receivers are represented by Webhooks and are expected to be valid HTTP endpoints. The
-	// Service also allows.
+	// list of receivers. The receivers are represented by Webhooks and are expected to be valid HTTP endpoints.
+	//
+	// Once configured, a Service is safe for concurrent use. Use SetDeliveryOptions to send to multiple webhooks in
+	// parallel.
 	Service struct {
 		client        *http.Client
 		webhooks      []*Webhook
 		preSendHooks  []PreSendHookFn
 		postSendHooks []PostSendHookFn
 		Serializer    Serializer
+		delivery      notify.DeliveryOptions
+		now           func() time.Time
+
+		clientsMu sync.Mutex
+		clients   map[*TransportOptions]*http.Client
 	}
 )
 
@@ -65,12 +99,19 @@ type defaultMarshaller struct{}
 
 // Marshal takes a payload and serializes it to a byte slice. The content type is used to determine the serialization
 // format. If the content type is not supported, an error is returned. The default marshaller supports the following
-// content types: application/json, text/plain.
-// NOTE: should we expand the default marshaller to support more content types?
+// content types: application/json, text/plain, application/x-www-form-urlencoded, application/xml, text/xml, and
+// multipart/form-data. A TemplatePayload is rendered regardless of the content type.
 func (defaultMarshaller) Marshal(contentType string, payload any) ([]byte, error) {
 	var out []byte
 	var err error
 
+	switch p := payload.(type) {
+	case TemplatePayload:
+		return marshalTemplate(p)
+	case *TemplatePayload:
+		return marshalTemplate(*p)
+	}
+
 	switch {
 	case strings.HasPrefix(contentType, "application/json"):
 		out, err = json.Marshal(payload)
@@ -83,6 +124,12 @@ func (defaultMarshaller) Marshal(contentType string, payload any) ([]byte, error
 			return nil, fmt.Errorf("payload was expected to be of type string, got %T", payload)
 		}
 		out = []byte(str)
+	case strings.HasPrefix(contentType, ContentTypeForm):
+		return marshalForm(payload)
+	case strings.HasPrefix(contentType, "application/xml"), strings.HasPrefix(contentType, "text/xml"):
+		return marshalXML(payload)
+	case strings.HasPrefix(contentType, ContentTypeMultipart):
+		return marshalMultipart(contentType, payload)
 	default:
 		return nil, errors.New("unsupported content type")
 	}
@@ -108,6 +155,8 @@ func New() *Service {
 		preSendHooks:  []PreSendHookFn{},
 		postSendHooks: []PostSendHookFn{},
 		Serializer:    defaultMarshaller{},
+		clients:       map[*TransportOptions]*http.Client{},
+		now:           time.Now,
 	}
 }
 
@@ -144,11 +193,21 @@ func (s *Service) AddReceiversURLs(urls ...string) {
 	}
 }
 
+// SetDeliveryOptions configures how the Send method delivers a message to multiple webhooks. By default, webhooks are
+// processed sequentially and delivery stops at the first error.
+func (s *Service) SetDeliveryOptions(opts notify.DeliveryOptions) {
+	s.delivery = opts
+}
+
 // WithClient sets the http client to be used for sending requests. Calling this method is optional, the default client
-// will be used if this method is not called.
+// will be used if this method is not called. Webhooks with TransportOptions use a copy of the client.
 func (s *Service) WithClient(client *http.Client) {
 	if client != nil {
 		s.client = client
+
+		s.clientsMu.Lock()
+		clear(s.clients)
+		s.clientsMu.Unlock()
 	}
 }
 
@@ -176,7 +235,9 @@ func (s *Service) doPostSendHooks(req *http.Request, resp *http.Response) error
 	return nil
 }
 
-// PreSend adds a pre-send hook to the service. The hook will be executed before sending a request to a receiver.
+// PreSend adds a pre-send hook to the service. The hook will be executed before sending a request to a receiver. Hooks
+// run before the request is authenticated and signed, so changes they make to the header or the body are covered by
+// the signature.
 func (s *Service) PreSend(hook PreSendHookFn) {
 	s.preSendHooks = append(s.preSendHooks, hook)
 }
@@ -188,48 +249,68 @@ func (s *Service) PostSend(hook PostSendHookFn) {
 
 // newRequest creates a new http request with the given method, content-type, url and payload. Request created by this
 // function will usually be passed to the Service.do method.
-func newRequest(ctx context.Context, hook *Webhook, payload io.Reader) (*http.Request, error) {
+func newRequest(ctx context.Context, hook *Webhook, contentType string, payload io.Reader) (*http.Request, error) {
 	req, err := http.NewRequestWithContext(ctx, hook.Method, hook.URL, payload)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header = hook.Header
+	// Every request gets its own copy of the header, so that pre-send hooks and concurrent sends don't modify the
+	// header of the webhook itself.
+	req.Header = hook.Header.Clone()
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
 
 	if req.Header.Get("User-Agent") == "" {
 		req.Header.Set("User-Agent", defaultUserAgent)
 	}
 	if req.Header.Get("Content-Type") == "" {
-		req.Header.Set("Content-Type", hook.ContentType)
+		req.Header.Set("Content-Type", contentType)
 	}
 
 	return req, nil
 }
 
-// do sends the given request and returns an error if the request failed. A failed request gets identified by either
-// an unsuccessful status code or a non-nil error. The given request is expected to be valid and was usually created
-// by the newRequest function.
-func (s *Service) do(req *http.Request) error {
-	// Execute all pre-send hooks in order.
-	if err := s.doPreSendHooks(req); err != nil {
-		return fmt.Errorf("pre-send hooks: %w", err)
-	}
-
+// do sends the given request and returns an error if the request failed. Pre-send hooks were executed already. A failed request gets identified by either
+// a response that is rejected by the given validator, an unsuccessful status code if there is no validator, or a
+// non-nil error. The given request is expected to be valid and was usually created by the newRequest function.
+func (s *Service) do(client *http.Client, req *http.Request, validate ResponseValidatorFn) error {
 	// Actually send the HTTP request.
-	resp, err := s.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	body, err := readBody(resp)
+	if err != nil {
+		return err
+	}
+
 	// Execute all post-send hooks in order.
 	if err = s.doPostSendHooks(req, resp); err != nil {
 		return fmt.Errorf("post-send hooks: %w", err)
 	}
 
-	// Check if response code is 2xx. Should this be configurable?
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("responded with status code: %d", resp.StatusCode)
+	respErr := &ResponseError{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       bodyExcerpt(body),
+	}
+
+	// Without a validator, every 2xx response is a success.
+	if validate == nil {
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return respErr
+		}
+
+		return nil
+	}
+
+	if err = validate(resp, body); err != nil {
+		respErr.Reason = err.Error()
+		return respErr
 	}
 
 	return nil
@@ -237,45 +318,167 @@ func (s *Service) do(req *http.Request) error {
 
 // send is a helper method that sends a message to a single webhook. It wraps the core logic of the Send method, which
 // is creating a new request for the given webhook and sending it.
-func (s *Service) send(ctx context.Context, webhook *Webhook, payload []byte) error {
+func (s *Service) send(ctx context.Context, webhook *Webhook, contentType string, payload []byte) error {
+	var eventHeader http.Header
+	if webhook.CloudEvents != nil {
+		var err error
+		payload, eventHeader, err = webhook.CloudEvents.encode(contentType, payload, s.now())
+		if err != nil {
+			return fmt.Errorf("encode cloudevent: %w", err)
+		}
+	}
+
+	if webhook.Auth != nil {
+		ctx = withAuthClient(ctx, s.clientFor(webhook))
+	}
+
 	// Create a new HTTP request for the given webhook.
-	req, err := newRequest(ctx, webhook, bytes.NewReader(payload))
+	req, err := newRequest(ctx, webhook, contentType, bytes.NewReader(payload))
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
 	defer func() { _ = req.Body.Close() }()
 
-	return s.do(req)
+	for key, values := range eventHeader {
+		req.Header[key] = values
+	}
+
+	if webhook.PriorityHeader != "" {
+		if priority, ok := notify.PriorityFromContext(ctx); ok {
+			req.Header.Set(webhook.PriorityHeader, strconv.Itoa(int(priority)))
+		}
+	}
+
+	// Execute all pre-send hooks in order, before authenticating and signing the request.
+	if len(s.preSendHooks) > 0 {
+		if err = s.doPreSendHooks(req); err != nil {
+			return fmt.Errorf("pre-send hooks: %w", err)
+		}
+
+		// The hooks may have replaced the body, which the signature has to cover.
+		if payload, err = readRequestBody(req); err != nil {
+			return fmt.Errorf("read request body: %w", err)
+		}
+	}
+
+	if webhook.Auth != nil {
+		if err = webhook.Auth.Authenticate(req, payload); err != nil {
+			return fmt.Errorf("authenticate request: %w", err)
+		}
+	}
+
+	// Sign last, so that the signature covers the final request.
+	if webhook.Signer != nil {
+		if err = webhook.Signer.Sign(req, payload); err != nil {
+			return fmt.Errorf("sign request: %w", err)
+		}
+	}
+
+	return s.do(s.clientFor(webhook), req, webhook.ValidateResponse)
+}
+
+// readRequestBody reads the body of the given request and replaces it with a copy that can be read again.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	payload, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(payload))
+	req.ContentLength = int64(len(payload))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(payload)), nil
+	}
+
+	return payload, nil
+}
+
+// checkWebhook sends a HEAD request to the given webhook. Only responses that indicate invalid credentials, a missing
+// endpoint, or a server error are treated as failures, since many webhook endpoints don't implement the HEAD method.
+func (s *Service) checkWebhook(ctx context.Context, webhook *Webhook) error {
+	if webhook.Auth != nil {
+		ctx = withAuthClient(ctx, s.clientFor(webhook))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, webhook.URL, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header = webhook.Header.Clone()
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", defaultUserAgent)
+	}
+
+	if webhook.Auth != nil {
+		if err = webhook.Auth.Authenticate(req, nil); err != nil {
+			return fmt.Errorf("authenticate request: %w", err)
+		}
+	}
+
+	resp, err := s.clientFor(webhook).Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	kind, _ := notify.ClassifyStatusCode(resp.StatusCode)
+	if errors.Is(kind, notify.ErrAuthFailure) || errors.Is(kind, notify.ErrInvalidReceiver) ||
+		resp.StatusCode >= http.StatusInternalServerError {
+		return &ResponseError{StatusCode: resp.StatusCode, Header: resp.Header}
+	}
+
+	return nil
+}
+
+// Check sends a HEAD request to every webhook to verify that it is reachable. It implements the notify.Checker
+// interface. Pre- and post-send hooks are not executed.
+func (s *Service) Check(ctx context.Context) error {
+	var errs []error
+	for _, webhook := range s.webhooks {
+		if webhook == nil {
+			continue
+		}
+
+		if err := s.checkWebhook(ctx, webhook); err != nil {
+			errs = append(errs, notify.NewReceiverError("http", webhook.URL, fmt.Errorf("check %s: %w", webhook.URL, err)))
+		}
+	}
+
+	return errors.Join(errs...)
 }
 
 // Send takes a message and sends it to all webhooks.
 func (s *Service) Send(ctx context.Context, subject, message string) error {
 	// Send message to all webhooks.
-	for _, webhook := range s.webhooks {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			// Skip webhook if it is nil.
-			if webhook == nil {
-				continue
-			}
-
-			// Build the payload for the current webhook.
-			payload := webhook.BuildPayload(subject, message)
-
-			// Marshal the message into a payload.
-			payloadRaw, err := s.Serializer.Marshal(webhook.ContentType, payload)
-			if err != nil {
-				return fmt.Errorf("marshal payload: %w", err)
-			}
-
-			// Send the payload to the webhook.
-			if err = s.send(ctx, webhook, payloadRaw); err != nil {
-				return fmt.Errorf("send to %s: %w", webhook.URL, err)
-			}
+	return notify.Deliver(ctx, s.delivery, s.webhooks, func(ctx context.Context, webhook *Webhook) error {
+		// Skip webhook if it is nil.
+		if webhook == nil {
+			return nil
 		}
-	}
 
-	return nil
+		// Build the payload for the current webhook.
+		payload := webhook.BuildPayload(subject, message)
+
+		// Marshal the message into a payload. Multipart bodies need a boundary that's shared with the header.
+		contentType := requestContentType(webhook.ContentType)
+		payloadRaw, err := s.Serializer.Marshal(contentType, payload)
+		if err != nil {
+			return fmt.Errorf("marshal payload: %w", err)
+		}
+
+		// Send the payload to the webhook.
+		if err = s.send(ctx, webhook, contentType, payloadRaw); err != nil {
+			return notify.NewReceiverError("http", webhook.URL, fmt.Errorf("send to %s: %w", webhook.URL, err))
+		}
+
+		return nil
+	})
 }
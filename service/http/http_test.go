@@ -9,10 +9,15 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/notify"
 )
 
 // Allows us to simulate an error returned from the server on a per-request basis.
@@ -68,6 +73,10 @@ func TestNew(t *testing.T) {
 
 	s2 := New()
 	assert.NotNil(t, s2, "service should not be nil")
+
+	// Functions can't be compared.
+	assert.NotNil(t, s1.now, "clock should be set")
+	s1.now, s2.now = nil, nil
 	assert.Equal(t, s1, s2, "services should be equal")
 }
 
@@ -467,3 +476,115 @@ func Test_defaultMarshaller_Marshal(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Check(t *testing.T) {
+	t.Parallel()
+
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusMethodNotAllowed) // Not implementing HEAD is fine.
+	}))
+	defer healthy.Close()
+
+	unauthorized := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer unauthorized.Close()
+
+	service := New()
+	service.AddReceiversURLs(healthy.URL)
+	require.NoError(t, service.Check(context.Background()))
+
+	service.AddReceiversURLs(unauthorized.URL)
+	err := service.Check(context.Background())
+	require.Error(t, err)
+	require.ErrorIs(t, err, notify.ErrAuthFailure)
+}
+
+func TestService_Send_PriorityHeader(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Priority"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	webhook := newWebhook(server.URL)
+	webhook.PriorityHeader = "Priority"
+
+	service := New()
+	service.AddReceivers(webhook)
+
+	ctx := context.Background()
+	require.NoError(t, service.Send(ctx, "subject", "message"))
+	require.NoError(t, service.Send(notify.WithPriority(ctx, notify.PriorityHigh), "subject", "message"))
+	require.NoError(t, service.Send(notify.WithSeverity(ctx, notify.SeverityCritical), "subject", "message"))
+
+	require.Equal(t, []string{"", "4", "5"}, got)
+	require.Empty(t, webhook.Header.Get("Priority"), "webhook header must not be modified")
+}
+
+func TestService_Send_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	header := http.Header{"X-Shared": {"value"}}
+	service := New()
+	for i := range 6 {
+		path := "/ok"
+		if i%3 == 0 {
+			path = "/fail"
+		}
+		service.AddReceivers(&Webhook{
+			ContentType:  defaultContentType,
+			Header:       header,
+			Method:       http.MethodPost,
+			URL:          server.URL + path + "?receiver=" + strconv.Itoa(i),
+			BuildPayload: buildDefaultPayload,
+		})
+	}
+	service.SetDeliveryOptions(notify.DeliveryOptions{ContinueOnError: true, Concurrency: 3})
+
+	// Pre-send hooks modify the header of their own request only.
+	service.PreSend(func(req *http.Request) error {
+		req.Header.Set("X-Receiver", req.URL.Query().Get("receiver"))
+		return nil
+	})
+
+	err := service.Send(context.Background(), "subject", "message")
+	require.Error(t, err)
+
+	receiverErrs := notify.ReceiverErrors(err)
+	require.Len(t, receiverErrs, 2)
+	require.Equal(t, server.URL+"/fail?receiver=0", receiverErrs[0].Receiver)
+	require.Equal(t, server.URL+"/fail?receiver=3", receiverErrs[1].Receiver)
+	require.True(t, notify.IsRetryable(err))
+
+	require.LessOrEqual(t, maxInFlight.Load(), int32(3))
+	require.Greater(t, maxInFlight.Load(), int32(1))
+	require.Equal(t, http.Header{"X-Shared": {"value"}}, header, "webhook header must not be modified")
+}
@@ -0,0 +1,175 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// maxResponseBodyBytes is the maximum number of bytes of a response body that are read for validation.
+	maxResponseBodyBytes = 64 << 10
+
+	// maxBodyExcerptBytes is the maximum number of bytes of a response body that are included in a ResponseError.
+	maxBodyExcerptBytes = 512
+)
+
+type (
+	// ResponseValidatorFn decides whether a response counts as success. It's called with the response and its body,
+	// of which at most 64 KiB are read. A returned error marks the send as failed and becomes the Reason of the
+	// resulting ResponseError.
+	ResponseValidatorFn func(resp *http.Response, body []byte) error
+
+	// ResponseError is returned when a receiver responded unsuccessfully. It carries everything needed to decide whether
+	// and when to retry, e.g. the Retry-After header. Use errors.As to retrieve it from the error returned by Send.
+	ResponseError struct {
+		// StatusCode is the status code of the response.
+		StatusCode int
+
+		// Header is the header of the response.
+		Header http.Header
+
+		// Body is an excerpt of the response body of at most 512 bytes.
+		Body string
+
+		// Reason is the error of the response validator that rejected the response, if any.
+		Reason string
+	}
+)
+
+// Error returns the status code, the rejection reason, and the body excerpt of the response.
+func (e *ResponseError) Error() string {
+	msg := fmt.Sprintf("responded with status code: %d", e.StatusCode)
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	if e.Body != "" {
+		msg += ": " + e.Body
+	}
+
+	return msg
+}
+
+// HTTPStatusCode returns the status code of the response. It's picked up by notify.NewReceiverError to classify the
+// error.
+func (e *ResponseError) HTTPStatusCode() int {
+	return e.StatusCode
+}
+
+// RetryAfter returns the delay requested by the Retry-After header of the response. It reports false if the header is
+// missing or malformed.
+func (e *ResponseError) RetryAfter() (time.Duration, bool) {
+	value := e.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+// bodyExcerpt returns the beginning of the given body as single line, truncated to maxBodyExcerptBytes.
+func bodyExcerpt(body []byte) string {
+	excerpt := strings.Join(strings.Fields(strings.ToValidUTF8(string(body), "\uFFFD")), " ")
+	if len(excerpt) <= maxBodyExcerptBytes {
+		return excerpt
+	}
+
+	excerpt = excerpt[:maxBodyExcerptBytes]
+	for !utf8.ValidString(excerpt) {
+		excerpt = excerpt[:len(excerpt)-1]
+	}
+
+	return excerpt + "…"
+}
+
+// readBody reads the beginning of the body of the given response and replaces the body, so that it can still be read
+// in full by the post-send hooks.
+func readBody(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+
+	return body, nil
+}
+
+// ExpectStatus returns a ResponseValidatorFn that accepts responses with one of the given status codes.
+func ExpectStatus(codes ...int) ResponseValidatorFn {
+	return func(resp *http.Response, _ []byte) error {
+		if !slices.Contains(codes, resp.StatusCode) {
+			return fmt.Errorf("unexpected status code, want one of %v", codes)
+		}
+
+		return nil
+	}
+}
+
+// ExpectJSONField returns a ResponseValidatorFn that accepts JSON responses whose field at the given path equals the
+// given value. The path consists of object keys separated by dots, e.g. "result.status". It doesn't check the status
+// code; use ExpectAll to combine it with ExpectStatus.
+//
+// For example, ExpectJSONField("ok", true) rejects the responses of Slack-style APIs that answer failures with status
+// 200 and {"ok": false}.
+func ExpectJSONField(path string, want any) ResponseValidatorFn {
+	// Normalize the wanted value to the types produced by encoding/json, e.g. float64 for all numbers.
+	var normalized any
+	if raw, err := json.Marshal(want); err == nil {
+		_ = json.Unmarshal(raw, &normalized)
+	}
+
+	return func(_ *http.Response, body []byte) error {
+		var value any
+		if err := json.Unmarshal(body, &value); err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+
+		for _, key := range strings.Split(path, ".") {
+			object, ok := value.(map[string]any)
+			if !ok {
+				return fmt.Errorf("field %q not found", path)
+			}
+			if value, ok = object[key]; !ok {
+				return fmt.Errorf("field %q not found", path)
+			}
+		}
+
+		if !reflect.DeepEqual(value, normalized) {
+			return fmt.Errorf("field %q is %v, want %v", path, value, want)
+		}
+
+		return nil
+	}
+}
+
+// ExpectAll returns a ResponseValidatorFn that accepts responses that are accepted by all the given validators.
+func ExpectAll(validators ...ResponseValidatorFn) ResponseValidatorFn {
+	return func(resp *http.Response, body []byte) error {
+		for _, validate := range validators {
+			if err := validate(resp, body); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,190 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/notify"
+)
+
+// newResponseServer returns a server that answers every request with the given status code, header, and body.
+func newResponseServer(t *testing.T, status int, header http.Header, body string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		for key, values := range header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestService_ResponseError(t *testing.T) {
+	t.Parallel()
+
+	server := newResponseServer(t, http.StatusTooManyRequests, http.Header{"Retry-After": {"30"}},
+		"{\n  \"error\": \"slow down\"\n}")
+
+	service := New()
+	service.AddReceiversURLs(server.URL)
+
+	err := service.Send(context.Background(), "subject", "message")
+	require.ErrorContains(t, err, `responded with status code: 429: { "error": "slow down" }`)
+	require.ErrorIs(t, err, notify.ErrRateLimited)
+	require.True(t, notify.IsRetryable(err))
+
+	var respErr *ResponseError
+	require.ErrorAs(t, err, &respErr)
+	require.Equal(t, http.StatusTooManyRequests, respErr.StatusCode)
+
+	retryAfter, ok := respErr.RetryAfter()
+	require.True(t, ok)
+	require.Equal(t, 30*time.Second, retryAfter)
+}
+
+func TestService_ValidateResponse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		validate ResponseValidatorFn
+		wantErr  string
+	}{
+		{
+			name:     "json field matches",
+			status:   http.StatusOK,
+			body:     `{"ok": true}`,
+			validate: ExpectJSONField("ok", true),
+		},
+		{
+			name:     "json field does not match",
+			status:   http.StatusOK,
+			body:     `{"ok": false, "error": "channel_not_found"}`,
+			validate: ExpectJSONField("ok", true),
+			wantErr: `responded with status code: 200: field "ok" is false, want true: ` +
+				`{"ok": false, "error": "channel_not_found"}`,
+		},
+		{
+			name:     "nested json field",
+			status:   http.StatusOK,
+			body:     `{"result": {"code": 0}}`,
+			validate: ExpectJSONField("result.code", 0),
+		},
+		{
+			name:     "missing json field",
+			status:   http.StatusOK,
+			body:     `{"result": "done"}`,
+			validate: ExpectJSONField("result.code", 0),
+			wantErr:  `field "result.code" not found`,
+		},
+		{
+			name:     "invalid json",
+			status:   http.StatusOK,
+			body:     `ok`,
+			validate: ExpectJSONField("ok", true),
+			wantErr:  "decode response",
+		},
+		{
+			name:     "expected status",
+			status:   http.StatusNotModified,
+			validate: ExpectStatus(http.StatusOK, http.StatusNotModified),
+		},
+		{
+			name:     "unexpected status",
+			status:   http.StatusAccepted,
+			validate: ExpectStatus(http.StatusOK),
+			wantErr:  "responded with status code: 202: unexpected status code, want one of [200]",
+		},
+		{
+			name:     "all validators",
+			status:   http.StatusInternalServerError,
+			body:     `{"ok": true}`,
+			validate: ExpectAll(ExpectStatus(http.StatusOK), ExpectJSONField("ok", true)),
+			wantErr:  "unexpected status code",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newResponseServer(t, tt.status, nil, tt.body)
+
+			webhook := newWebhook(server.URL)
+			webhook.ValidateResponse = tt.validate
+
+			service := New()
+			service.AddReceivers(webhook)
+
+			err := service.Send(context.Background(), "subject", "message")
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+
+			var respErr *ResponseError
+			require.ErrorAs(t, err, &respErr)
+			require.Equal(t, tt.status, respErr.StatusCode)
+		})
+	}
+}
+
+func TestService_PostSendHookReadsFullBody(t *testing.T) {
+	t.Parallel()
+
+	body := strings.Repeat("a", maxResponseBodyBytes+10)
+	server := newResponseServer(t, http.StatusOK, nil, body)
+
+	var read string
+	service := New()
+	service.AddReceiversURLs(server.URL)
+	service.PostSend(func(_ *http.Request, resp *http.Response) error {
+		raw, err := io.ReadAll(resp.Body)
+		read = string(raw)
+
+		return err
+	})
+
+	require.NoError(t, service.Send(context.Background(), "subject", "message"))
+	require.Equal(t, body, read)
+}
+
+func TestBodyExcerpt(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, bodyExcerpt(nil))
+	require.Equal(t, "a b", bodyExcerpt([]byte("a\n\tb\n")))
+
+	excerpt := bodyExcerpt([]byte(strings.Repeat("ä", maxBodyExcerptBytes)))
+	require.True(t, strings.HasSuffix(excerpt, "ä…"))
+	require.LessOrEqual(t, len(excerpt), maxBodyExcerptBytes+len("…"))
+}
+
+func TestResponseError_RetryAfter(t *testing.T) {
+	t.Parallel()
+
+	_, ok := (&ResponseError{Header: http.Header{}}).RetryAfter()
+	require.False(t, ok)
+
+	_, ok = (&ResponseError{Header: http.Header{"Retry-After": {"soon"}}}).RetryAfter()
+	require.False(t, ok)
+
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	retryAfter, ok := (&ResponseError{Header: http.Header{"Retry-After": {date}}}).RetryAfter()
+	require.True(t, ok)
+	require.InDelta(t, time.Minute, retryAfter, float64(2*time.Second))
+}
@@ -0,0 +1,223 @@
+package http
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// These are the content types supported by the default serializer, in addition to application/json and text/plain.
+const (
+	ContentTypeForm      = "application/x-www-form-urlencoded"
+	ContentTypeXML       = "application/xml; charset=utf-8"
+	ContentTypeMultipart = "multipart/form-data"
+)
+
+// xmlRootElement is the name of the root element of XML documents that are built from a map.
+const xmlRootElement = "notification"
+
+type (
+	// MultipartPayload is a payload for multipart/form-data requests. It's built by a BuildPayloadFn, e.g. to upload a
+	// file together with the message.
+	MultipartPayload struct {
+		// Fields are the plain form fields. They are written in the order of their names.
+		Fields map[string]string
+
+		// Attachments are the files that are uploaded after the fields.
+		Attachments []Attachment
+	}
+
+	// Attachment is a file of a MultipartPayload.
+	Attachment struct {
+		// FieldName is the name of the form field. Defaults to "file".
+		FieldName string
+
+		// FileName is the name of the file.
+		FileName string
+
+		// ContentType is the content type of the file. Defaults to application/octet-stream.
+		ContentType string
+
+		// Data is the content of the file.
+		Data []byte
+	}
+
+	// TemplatePayload is a payload that is rendered from a template, regardless of the content type of the webhook.
+	// It allows talking to endpoints that expect a fixed body format, e.g. a legacy SOAP API, without writing a
+	// Serializer. Use BuildTemplatePayload to create a BuildPayloadFn that returns it.
+	TemplatePayload struct {
+		Template *template.Template
+		Data     any
+	}
+
+	// TemplateData is the data that templates of BuildTemplatePayload are executed with.
+	TemplateData struct {
+		Subject string
+		Message string
+	}
+)
+
+// BuildTemplatePayload returns a BuildPayloadFn that renders the body of the request from the given template. The
+// template is executed with TemplateData, e.g. "{{.Subject}}: {{.Message}}". Use the built-in html function to escape
+// the values for XML bodies.
+func BuildTemplatePayload(tmpl *template.Template) BuildPayloadFn {
+	return func(subject, message string) any {
+		return TemplatePayload{
+			Template: tmpl,
+			Data:     TemplateData{Subject: subject, Message: message},
+		}
+	}
+}
+
+// requestContentType returns the content type a request is sent with. Multipart content types without a boundary get
+// a random one, so that the serializer and the Content-Type header agree on it.
+func requestContentType(contentType string) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] != "" {
+		return contentType
+	}
+
+	return contentType + "; boundary=" + multipart.NewWriter(io.Discard).Boundary()
+}
+
+// marshalTemplate renders the given template payload.
+func marshalTemplate(payload TemplatePayload) ([]byte, error) {
+	if payload.Template == nil {
+		return nil, errors.New("template payload without template")
+	}
+
+	var buf bytes.Buffer
+	if err := payload.Template.Execute(&buf, payload.Data); err != nil {
+		return nil, fmt.Errorf("execute template: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// marshalForm encodes the given payload as form. Supported payload types are url.Values, map[string]string, and
+// map[string][]string.
+func marshalForm(payload any) ([]byte, error) {
+	var values url.Values
+
+	switch p := payload.(type) {
+	case url.Values:
+		values = p
+	case map[string][]string:
+		values = p
+	case map[string]string:
+		values = make(url.Values, len(p))
+		for key, value := range p {
+			values.Set(key, value)
+		}
+	default:
+		return nil, fmt.Errorf("payload was expected to be a map of strings or url.Values, got %T", payload)
+	}
+
+	return []byte(values.Encode()), nil
+}
+
+// marshalXML encodes the given payload as XML document. A map[string]string is written as flat document with the root
+// element "notification" and one element per key, in the order of the keys; all other payloads are encoded using
+// encoding/xml.
+func marshalXML(payload any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	fields, ok := payload.(map[string]string)
+	if !ok {
+		out, err := xml.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("marshal payload: %w", err)
+		}
+		buf.Write(out)
+
+		return buf.Bytes(), nil
+	}
+
+	encoder := xml.NewEncoder(&buf)
+	root := xml.StartElement{Name: xml.Name{Local: xmlRootElement}}
+	if err := encoder.EncodeToken(root); err != nil {
+		return nil, err
+	}
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		if err := encoder.EncodeElement(fields[key], xml.StartElement{Name: xml.Name{Local: key}}); err != nil {
+			return nil, fmt.Errorf("marshal field %q: %w", key, err)
+		}
+	}
+	if err := encoder.EncodeToken(root.End()); err != nil {
+		return nil, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// quoteEscaper escapes the names of multipart form fields and files.
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// marshalMultipart encodes the given payload as multipart form with the boundary of the given content type. Supported
+// payload types are MultipartPayload and map[string]string.
+func marshalMultipart(contentType string, payload any) ([]byte, error) {
+	var p MultipartPayload
+	switch v := payload.(type) {
+	case MultipartPayload:
+		p = v
+	case *MultipartPayload:
+		p = *v
+	case map[string]string:
+		p.Fields = v
+	default:
+		return nil, fmt.Errorf("payload was expected to be of type MultipartPayload or map[string]string, got %T", payload)
+	}
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("parse content type: %w", err)
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	if err = writer.SetBoundary(params["boundary"]); err != nil {
+		return nil, fmt.Errorf("set boundary: %w", err)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(p.Fields)) {
+		if err = writer.WriteField(key, p.Fields[key]); err != nil {
+			return nil, fmt.Errorf("write field %q: %w", key, err)
+		}
+	}
+
+	for _, attachment := range p.Attachments {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(cmp.Or(attachment.FieldName, "file")), quoteEscaper.Replace(attachment.FileName)))
+		header.Set("Content-Type", cmp.Or(attachment.ContentType, "application/octet-stream"))
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, fmt.Errorf("create part for %q: %w", attachment.FileName, err)
+		}
+		if _, err = part.Write(attachment.Data); err != nil {
+			return nil, fmt.Errorf("write attachment %q: %w", attachment.FileName, err)
+		}
+	}
+
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
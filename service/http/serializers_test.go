@@ -0,0 +1,130 @@
+package http
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultMarshaller_Form(t *testing.T) {
+	t.Parallel()
+
+	out, err := defaultMarshaller{}.Marshal(ContentTypeForm, map[string]string{"subject": "a b", "message": "c&d"})
+	require.NoError(t, err)
+	require.Equal(t, "message=c%26d&subject=a+b", string(out))
+
+	out, err = defaultMarshaller{}.Marshal(ContentTypeForm, url.Values{"tag": {"x", "y"}})
+	require.NoError(t, err)
+	require.Equal(t, "tag=x&tag=y", string(out))
+
+	_, err = defaultMarshaller{}.Marshal(ContentTypeForm, "subject")
+	require.ErrorContains(t, err, "got string")
+}
+
+func TestDefaultMarshaller_XML(t *testing.T) {
+	t.Parallel()
+
+	out, err := defaultMarshaller{}.Marshal(ContentTypeXML, map[string]string{"subject": "Disk <full>", "message": "92%"})
+	require.NoError(t, err)
+	require.Equal(t, xml.Header+
+		"<notification><message>92%</message><subject>Disk &lt;full&gt;</subject></notification>", string(out))
+
+	type alert struct {
+		XMLName xml.Name `xml:"alert"`
+		Level   string   `xml:"level,attr"`
+		Text    string   `xml:"text"`
+	}
+
+	out, err = defaultMarshaller{}.Marshal("text/xml", alert{Level: "high", Text: "boom"})
+	require.NoError(t, err)
+	require.Equal(t, xml.Header+`<alert level="high"><text>boom</text></alert>`, string(out))
+}
+
+func TestDefaultMarshaller_Template(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(template.New("soap").Parse(`<Envelope><Text>{{html .Subject}}: {{html .Message}}</Text></Envelope>`))
+	payload := BuildTemplatePayload(tmpl)("a&b", "c")
+
+	// Templates are rendered regardless of the content type.
+	out, err := defaultMarshaller{}.Marshal("application/soap+xml", payload)
+	require.NoError(t, err)
+	require.Equal(t, "<Envelope><Text>a&amp;b: c</Text></Envelope>", string(out))
+
+	_, err = defaultMarshaller{}.Marshal("text/plain", TemplatePayload{})
+	require.ErrorContains(t, err, "without template")
+}
+
+func TestService_SendMultipart(t *testing.T) {
+	t.Parallel()
+
+	type upload struct {
+		fields      map[string][]string
+		fileName    string
+		contentType string
+		data        string
+	}
+
+	uploads := make(chan upload, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		file, header, err := r.FormFile("log")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer func() { _ = file.Close() }()
+		data, _ := io.ReadAll(file)
+
+		uploads <- upload{
+			fields:      r.MultipartForm.Value,
+			fileName:    header.Filename,
+			contentType: header.Header.Get("Content-Type"),
+			data:        string(data),
+		}
+	}))
+	defer server.Close()
+
+	service := New()
+	service.AddReceivers(&Webhook{
+		ContentType: ContentTypeMultipart,
+		Header:      http.Header{},
+		Method:      http.MethodPost,
+		URL:         server.URL,
+		BuildPayload: func(subject, message string) any {
+			return MultipartPayload{
+				Fields: map[string]string{"subject": subject, "message": message},
+				Attachments: []Attachment{
+					{FieldName: "log", FileName: `build "42".log`, ContentType: "text/plain", Data: []byte("failed")},
+				},
+			}
+		},
+	})
+
+	require.NoError(t, service.Send(context.Background(), "Build failed", "See log"))
+
+	got := <-uploads
+	require.Equal(t, map[string][]string{"subject": {"Build failed"}, "message": {"See log"}}, got.fields)
+	require.Equal(t, `build "42".log`, got.fileName)
+	require.Equal(t, "text/plain", got.contentType)
+	require.Equal(t, "failed", got.data)
+}
+
+func TestRequestContentType(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "application/json", requestContentType("application/json"))
+	require.Equal(t, "multipart/form-data; boundary=abc", requestContentType("multipart/form-data; boundary=abc"))
+	require.Regexp(t, `^multipart/form-data; boundary=[0-9a-f]+$`, requestContentType(ContentTypeMultipart))
+}
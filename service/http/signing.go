@@ -0,0 +1,230 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// These are the headers used by the signers of this package.
+const (
+	HeaderWebhookID        = "Webhook-Id"
+	HeaderWebhookTimestamp = "Webhook-Timestamp"
+	HeaderWebhookSignature = "Webhook-Signature"
+	HeaderHubSignature256  = "X-Hub-Signature-256"
+)
+
+const (
+	// DefaultSignatureTolerance is the maximum age of a timestamped signature that is accepted by default.
+	DefaultSignatureTolerance = 5 * time.Minute
+
+	// standardWebhooksSecretPrefix is the prefix of secrets in the format used by Standard Webhooks.
+	standardWebhooksSecretPrefix = "whsec_"
+
+	// defaultMaxVerifiedBodyBytes is the maximum size of a request body that VerifySignature accepts.
+	defaultMaxVerifiedBodyBytes = 1 << 20
+)
+
+// ErrInvalidSignature signals that a request carries no signature or none that matches its body.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+type (
+	// Signer signs requests, so that their receivers can verify their authenticity. It's called with the final request,
+	// right before the pre-send hooks, and the payload that forms its body.
+	Signer interface {
+		Sign(req *http.Request, payload []byte) error
+	}
+
+	// Verifier verifies the signatures of received requests. It's the counterpart of a Signer.
+	Verifier interface {
+		Verify(header http.Header, payload []byte) error
+	}
+
+	// StandardWebhooksSigner signs and verifies requests according to the Standard Webhooks specification. It sets the
+	// headers webhook-id, webhook-timestamp, and webhook-signature, where the signature is an HMAC-SHA256 of the ID,
+	// the timestamp, and the body.
+	//
+	// See https://www.standardwebhooks.com.
+	StandardWebhooksSigner struct {
+		key       []byte
+		tolerance time.Duration
+		now       func() time.Time
+	}
+
+	// HubSigner signs and verifies requests the way GitHub does. It sets the header X-Hub-Signature-256 to the
+	// hex-encoded HMAC-SHA256 of the body, prefixed with "sha256=".
+	HubSigner struct {
+		secret []byte
+	}
+)
+
+// Compile-time checks to ensure the signers implement Signer and Verifier.
+var (
+	_ Signer   = (*StandardWebhooksSigner)(nil)
+	_ Verifier = (*StandardWebhooksSigner)(nil)
+	_ Signer   = (*HubSigner)(nil)
+	_ Verifier = (*HubSigner)(nil)
+)
+
+// NewStandardWebhooksSigner returns a new StandardWebhooksSigner for the given secret. The secret is expected to be
+// base64 encoded and may carry the prefix "whsec_", which is the format most providers use.
+func NewStandardWebhooksSigner(secret string) (*StandardWebhooksSigner, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, standardWebhooksSecretPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("decode secret: %w", err)
+	}
+	if len(key) == 0 {
+		return nil, errors.New("secret is empty")
+	}
+
+	return &StandardWebhooksSigner{
+		key:       key,
+		tolerance: DefaultSignatureTolerance,
+		now:       time.Now,
+	}, nil
+}
+
+// SetTolerance sets the maximum difference between the timestamp of a request and the current time that Verify
+// accepts. It defaults to DefaultSignatureTolerance and protects against replay attacks.
+func (s *StandardWebhooksSigner) SetTolerance(tolerance time.Duration) {
+	s.tolerance = tolerance
+}
+
+// signature returns the signature of the given message in the format "v1,<base64>".
+func (s *StandardWebhooksSigner) signature(id, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(id + "." + timestamp + "."))
+	mac.Write(payload)
+
+	return "v1," + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// webhookIDKey is the context key for the webhook-id of a message.
+type webhookIDKey struct{}
+
+// WithWebhookID returns a copy of the given context that carries the webhook-id that StandardWebhooksSigner uses for the
+// message, e.g. to keep the id of a message when it's sent again, so that receivers can deduplicate it.
+func WithWebhookID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, webhookIDKey{}, id)
+}
+
+// Sign sets the Standard Webhooks headers of the given request. The webhook-id is taken from the context of the request,
+// see WithWebhookID; otherwise a random one is generated for every message. Receivers deduplicate messages by their
+// id, so it must not be set through the header of the Webhook.
+func (s *StandardWebhooksSigner) Sign(req *http.Request, payload []byte) error {
+	id, _ := req.Context().Value(webhookIDKey{}).(string)
+	if id == "" {
+		raw := make([]byte, 16)
+		if _, err := rand.Read(raw); err != nil {
+			return fmt.Errorf("generate webhook id: %w", err)
+		}
+		id = "msg_" + hex.EncodeToString(raw)
+	}
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+
+	req.Header.Set(HeaderWebhookID, id)
+	req.Header.Set(HeaderWebhookTimestamp, timestamp)
+	req.Header.Set(HeaderWebhookSignature, s.signature(id, timestamp, payload))
+
+	return nil
+}
+
+// Verify checks the Standard Webhooks headers of a received request against its body. The webhook-signature header may
+// hold multiple space separated signatures, e.g. during the rotation of a secret; one valid signature is enough.
+func (s *StandardWebhooksSigner) Verify(header http.Header, payload []byte) error {
+	id := header.Get(HeaderWebhookID)
+	timestamp := header.Get(HeaderWebhookTimestamp)
+	signatures := header.Get(HeaderWebhookSignature)
+	if id == "" || timestamp == "" || signatures == "" {
+		return fmt.Errorf("%w: missing webhook headers", ErrInvalidSignature)
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: malformed timestamp", ErrInvalidSignature)
+	}
+	if age := s.now().Sub(time.Unix(seconds, 0)); age > s.tolerance || age < -s.tolerance {
+		return fmt.Errorf("%w: timestamp outside of tolerance", ErrInvalidSignature)
+	}
+
+	expected := []byte(s.signature(id, timestamp, payload))
+	for _, signature := range strings.Fields(signatures) {
+		if hmac.Equal([]byte(signature), expected) {
+			return nil
+		}
+	}
+
+	return ErrInvalidSignature
+}
+
+// NewHubSigner returns a new HubSigner for the given secret.
+func NewHubSigner(secret string) *HubSigner {
+	return &HubSigner{secret: []byte(secret)}
+}
+
+// signature returns the signature of the given payload in the format "sha256=<hex>".
+func (s *HubSigner) signature(payload []byte) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sign sets the X-Hub-Signature-256 header of the given request.
+func (s *HubSigner) Sign(req *http.Request, payload []byte) error {
+	req.Header.Set(HeaderHubSignature256, s.signature(payload))
+
+	return nil
+}
+
+// Verify checks the X-Hub-Signature-256 header of a received request against its body.
+func (s *HubSigner) Verify(header http.Header, payload []byte) error {
+	signature := header.Get(HeaderHubSignature256)
+	if signature == "" {
+		return fmt.Errorf("%w: missing %s header", ErrInvalidSignature, HeaderHubSignature256)
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.signature(payload))) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// VerifySignature returns an http.Handler that verifies the signature of every request using the given verifier before
+// passing it on to the given handler. Requests with an invalid signature are answered with status 401. The body of the
+// request remains readable for the given handler. Bodies larger than 1 MiB are rejected with status 413.
+func VerifySignature(verifier Verifier, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, defaultMaxVerifiedBodyBytes))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			http.Error(w, "read body", http.StatusBadRequest)
+			return
+		}
+
+		if err = verifier.Verify(r.Header, payload); err != nil {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(payload))
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStandardWebhooksSigner(t *testing.T) {
+	t.Parallel()
+
+	// Test vector of the Standard Webhooks reference implementation.
+	signer, err := NewStandardWebhooksSigner("whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw")
+	require.NoError(t, err)
+	signer.now = func() time.Time { return time.Unix(1614265330, 0) }
+
+	payload := []byte(`{"test": 2432232314}`)
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req = req.WithContext(WithWebhookID(req.Context(), "msg_p5jXN8AQM9LWM0D4loKWxJek"))
+
+	require.NoError(t, signer.Sign(req, payload))
+	require.Equal(t, "msg_p5jXN8AQM9LWM0D4loKWxJek", req.Header.Get(HeaderWebhookID))
+	require.Equal(t, "1614265330", req.Header.Get(HeaderWebhookTimestamp))
+	require.Equal(t, "v1,g0hM9SsE+OTPJTGt/tmIKtSyZlE3uFJELVlNIOLJ1OE=", req.Header.Get(HeaderWebhookSignature))
+
+	require.NoError(t, signer.Verify(req.Header, payload))
+	require.ErrorIs(t, signer.Verify(req.Header, []byte(`{"test": 1}`)), ErrInvalidSignature)
+
+	// One valid signature out of several is enough.
+	req.Header.Set(HeaderWebhookSignature, "v1,bm9wZQ== "+req.Header.Get(HeaderWebhookSignature))
+	require.NoError(t, signer.Verify(req.Header, payload))
+
+	// Old requests are rejected.
+	signer.now = func() time.Time { return time.Unix(1614265330, 0).Add(10 * time.Minute) }
+	require.ErrorIs(t, signer.Verify(req.Header, payload), ErrInvalidSignature)
+
+	signer.SetTolerance(time.Hour)
+	require.NoError(t, signer.Verify(req.Header, payload))
+}
+
+func TestStandardWebhooksSigner_GeneratesID(t *testing.T) {
+	t.Parallel()
+
+	signer, err := NewStandardWebhooksSigner("c2VjcmV0")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	require.NoError(t, signer.Sign(req, nil))
+	id := req.Header.Get(HeaderWebhookID)
+	require.True(t, strings.HasPrefix(id, "msg_"))
+	require.NoError(t, signer.Verify(req.Header, nil))
+
+	// Every message gets a new id, even if the header already carries one.
+	require.NoError(t, signer.Sign(req, nil))
+	require.NotEqual(t, id, req.Header.Get(HeaderWebhookID))
+
+	_, err = NewStandardWebhooksSigner("whsec_not base64")
+	require.Error(t, err)
+	_, err = NewStandardWebhooksSigner("")
+	require.Error(t, err)
+}
+
+func TestHubSigner(t *testing.T) {
+	t.Parallel()
+
+	// Example of the GitHub documentation.
+	signer := NewHubSigner("It's a Secret to Everybody")
+	payload := []byte("Hello, World!")
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	require.NoError(t, signer.Sign(req, payload))
+	require.Equal(t,
+		"sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17",
+		req.Header.Get(HeaderHubSignature256),
+	)
+
+	require.NoError(t, signer.Verify(req.Header, payload))
+	require.ErrorIs(t, signer.Verify(req.Header, []byte("Hello, World?")), ErrInvalidSignature)
+	require.ErrorIs(t, signer.Verify(http.Header{}, payload), ErrInvalidSignature)
+}
+
+func TestService_SendSigned(t *testing.T) {
+	t.Parallel()
+
+	signer := NewHubSigner("secret")
+
+	var received []byte
+	server := httptest.NewServer(VerifySignature(signer, http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+	})))
+	defer server.Close()
+
+	webhook := newWebhook(server.URL)
+	webhook.Signer = signer
+
+	service := New()
+	service.AddReceivers(webhook)
+
+	require.NoError(t, service.Send(context.Background(), "subject", "message"))
+	require.JSONEq(t, `{"subject": "subject", "message": "message"}`, string(received))
+
+	// The header of the webhook itself is left untouched.
+	require.Empty(t, webhook.Header.Get(HeaderHubSignature256))
+
+	// Changes of pre-send hooks are covered by the signature.
+	service.PreSend(func(req *http.Request) error {
+		req.Body = io.NopCloser(strings.NewReader(`{"subject": "changed"}`))
+		return nil
+	})
+	require.NoError(t, service.Send(context.Background(), "subject", "message"))
+	require.JSONEq(t, `{"subject": "changed"}`, string(received))
+
+	// Requests signed with another secret are rejected.
+	webhook.Signer = NewHubSigner("other")
+	require.ErrorContains(t, service.Send(context.Background(), "subject", "message"), "status code: 401")
+}
+
+func TestVerifySignature_BodyTooLarge(t *testing.T) {
+	t.Parallel()
+
+	handler := VerifySignature(NewHubSigner("secret"), http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("handler must not be called")
+	}))
+
+	rec := httptest.NewRecorder()
+	body := strings.NewReader(strings.Repeat("a", defaultMaxVerifiedBodyBytes+1))
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", body))
+
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
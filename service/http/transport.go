@@ -0,0 +1,116 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// TransportOptions configure how the requests of a single Webhook are sent, e.g. to reach receivers that sit behind
+// different PKIs. The requests are sent with a copy of the client of the service, whose transport is adjusted by the
+// options. If that client uses a custom http.RoundTripper that is not an *http.Transport, the copy uses a clone of
+// http.DefaultTransport instead.
+//
+// The client is built on the first request and reused afterwards, so the options must not be modified once the webhook
+// was used.
+type TransportOptions struct {
+	// TLSConfig is the base TLS configuration. It's cloned before the other options are applied. Defaults to the TLS
+	// configuration of the transport of the service.
+	TLSConfig *tls.Config
+
+	// Certificates are the client certificates presented for mutual TLS, e.g. loaded using tls.LoadX509KeyPair.
+	Certificates []tls.Certificate
+
+	// RootCAs are the certificate authorities that server certificates are verified with, e.g. loaded using
+	// LoadCertPool. Defaults to the system pool.
+	RootCAs *x509.CertPool
+
+	// ServerName overrides the name that is sent via SNI and that the server certificate is verified against.
+	ServerName string
+
+	// Proxy returns the proxy to use for a request, e.g. http.ProxyURL. Defaults to the proxy of the transport of the
+	// service, which is usually taken from the environment.
+	Proxy func(*http.Request) (*url.URL, error)
+
+	// Timeout limits the time a request may take, including reading the response. Defaults to the timeout of the client
+	// of the service.
+	Timeout time.Duration
+}
+
+// LoadCertPool returns a certificate pool with the PEM encoded certificates of the given files, e.g. a CA bundle.
+func LoadCertPool(files ...string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	for _, file := range files {
+		pem, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("read certificates: %w", err)
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", file)
+		}
+	}
+
+	return pool, nil
+}
+
+// newClient returns a copy of the given client with a transport that is configured by the options.
+func (o *TransportOptions) newClient(base *http.Client) *http.Client {
+	transport, ok := base.Transport.(*http.Transport)
+	if !ok {
+		transport, _ = http.DefaultTransport.(*http.Transport)
+	}
+	transport = transport.Clone()
+
+	tlsConfig := transport.TLSClientConfig
+	if o.TLSConfig != nil {
+		tlsConfig = o.TLSConfig.Clone()
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if len(o.Certificates) > 0 {
+		tlsConfig.Certificates = o.Certificates
+	}
+	if o.RootCAs != nil {
+		tlsConfig.RootCAs = o.RootCAs
+	}
+	if o.ServerName != "" {
+		tlsConfig.ServerName = o.ServerName
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if o.Proxy != nil {
+		transport.Proxy = o.Proxy
+	}
+
+	client := *base
+	client.Transport = transport
+	if o.Timeout > 0 {
+		client.Timeout = o.Timeout
+	}
+
+	return &client
+}
+
+// clientFor returns the client that the requests of the given webhook are sent with.
+func (s *Service) clientFor(webhook *Webhook) *http.Client {
+	if webhook.Transport == nil {
+		return s.client
+	}
+
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+
+	client, ok := s.clients[webhook.Transport]
+	if !ok {
+		client = webhook.Transport.newClient(s.client)
+		s.clients[webhook.Transport] = client
+	}
+
+	return client
+}
@@ -0,0 +1,171 @@
+package http
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newClientCertificate returns a self-signed client certificate and a pool that contains it.
+func newClientCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "notify"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert}, pool
+}
+
+func TestTransportOptions_MutualTLS(t *testing.T) {
+	t.Parallel()
+
+	clientCert, clientCAs := newClientCertificate(t)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	serverCAs := x509.NewCertPool()
+	serverCAs.AddCert(server.Certificate())
+
+	service := New()
+
+	// The server certificate isn't trusted by the default client.
+	webhook := newWebhook(server.URL)
+	service.AddReceivers(webhook)
+	require.Error(t, service.Send(context.Background(), "subject", "message"))
+
+	// Without a client certificate, the handshake fails.
+	webhook.Transport = &TransportOptions{RootCAs: serverCAs}
+	require.Error(t, service.Send(context.Background(), "subject", "message"))
+
+	webhook.Transport = &TransportOptions{RootCAs: serverCAs, Certificates: []tls.Certificate{clientCert}}
+	require.NoError(t, service.Send(context.Background(), "subject", "message"))
+
+	// The certificate of the test server is valid for example.com, but not for other names.
+	webhook.Transport = &TransportOptions{
+		RootCAs:      serverCAs,
+		Certificates: []tls.Certificate{clientCert},
+		ServerName:   "example.com",
+	}
+	require.NoError(t, service.Send(context.Background(), "subject", "message"))
+
+	webhook.Transport = &TransportOptions{
+		RootCAs:      serverCAs,
+		Certificates: []tls.Certificate{clientCert},
+		ServerName:   "other.example.org",
+	}
+	require.ErrorContains(t, service.Send(context.Background(), "subject", "message"), "other.example.org")
+}
+
+func TestTransportOptions_ProxyAndTimeout(t *testing.T) {
+	t.Parallel()
+
+	proxied := make(chan string, 10)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied <- r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+
+	service := New()
+	service.AddReceivers(newWebhook("http://internal.example.com/hook"))
+	service.webhooks[0].Transport = &TransportOptions{Proxy: http.ProxyURL(proxyURL)}
+
+	require.NoError(t, service.Send(context.Background(), "subject", "message"))
+	require.Equal(t, "http://internal.example.com/hook", <-proxied)
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slow.Close()
+
+	service = New()
+	service.AddReceivers(newWebhook(slow.URL))
+	service.webhooks[0].Transport = &TransportOptions{Timeout: 20 * time.Millisecond}
+	require.ErrorContains(t, service.Send(context.Background(), "subject", "message"), "Client.Timeout exceeded")
+}
+
+func TestService_ClientFor(t *testing.T) {
+	t.Parallel()
+
+	service := New()
+	webhook := newWebhook("http://localhost")
+	require.Same(t, http.DefaultClient, service.clientFor(webhook))
+
+	webhook.Transport = &TransportOptions{Timeout: time.Second}
+	client := service.clientFor(webhook)
+	require.Same(t, client, service.clientFor(webhook), "clients must be reused")
+	require.Equal(t, time.Second, client.Timeout)
+	require.Zero(t, http.DefaultClient.Timeout, "the client of the service must not be modified")
+
+	// Setting a new client drops the derived ones.
+	service.WithClient(&http.Client{Timeout: time.Minute})
+	require.NotSame(t, client, service.clientFor(webhook))
+}
+
+func TestLoadCertPool(t *testing.T) {
+	t.Parallel()
+
+	cert, _ := newClientCertificate(t)
+
+	dir := t.TempDir()
+	bundle := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0o600))
+
+	pool, err := LoadCertPool(bundle)
+	require.NoError(t, err)
+	want := x509.NewCertPool()
+	want.AddCert(cert.Leaf)
+	require.True(t, pool.Equal(want))
+
+	empty := filepath.Join(dir, "empty.pem")
+	require.NoError(t, os.WriteFile(empty, []byte("no certificates"), 0o600))
+	_, err = LoadCertPool(empty)
+	require.ErrorContains(t, err, "no certificates found")
+
+	_, err = LoadCertPool(filepath.Join(dir, "missing.pem"))
+	require.ErrorContains(t, err, "read certificates")
+}
@@ -1,5 +1,13 @@
 package lark
 
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/nikoksr/notify"
+)
+
 // sender is an interface for sending a message to an already defined receiver.
 type sender interface {
 	Send(subject, message string) error
@@ -56,3 +64,43 @@ const (
 	email   receiverIDType = "email"
 	chatID  receiverIDType = "chat_id"
 )
+
+// apiError is returned when the Lark API responded with a non-zero error code.
+type apiError int
+
+func (e apiError) Error() string {
+	return fmt.Sprintf(
+		"send failed with error code %d, please see "+
+			"https://open.larksuite.com/document/ukTMukTMukTM/ugjM14COyUjL4ITN for details",
+		int(e),
+	)
+}
+
+// ErrorCode returns the Lark error code. It's picked up by notify.NewReceiverError.
+func (e apiError) ErrorCode() string {
+	return strconv.Itoa(int(e))
+}
+
+// newReceiverError wraps the given error into a notify.ReceiverError and classifies it based on the error code returned
+// by the Lark API.
+func newReceiverError(receiverID string, err error) *notify.ReceiverError {
+	rErr := notify.NewReceiverError("lark", receiverID, err)
+
+	var apiErr apiError
+	if !errors.As(err, &apiErr) {
+		return rErr
+	}
+
+	switch apiErr {
+	case 19021, 99991661, 99991663, 99991668: // Invalid signature or access token
+		rErr.SetKind(notify.ErrAuthFailure)
+	case 230002, 230013, 230017: // Bot not in chat or user not visible to the bot
+		rErr.SetKind(notify.ErrInvalidReceiver)
+	case 230025: // Message content too long
+		rErr.SetKind(notify.ErrPayloadTooLarge)
+	case 9499, 11232, 99991400: // Rate limit reached
+		rErr.SetKind(notify.ErrRateLimited)
+	}
+
+	return rErr
+}
@@ -71,7 +71,7 @@ func (c *CustomAppService) Send(ctx context.Context, subject, message string) er
 			return ctx.Err()
 		default:
 			if err := c.cli.SendTo(subject, message, id.id, string(id.typ)); err != nil {
-				return err
+				return newReceiverError(id.id, err)
 			}
 		}
 	}
@@ -108,11 +108,7 @@ func (l *larkClientGoLarkChatBot) SendTo(subject, message, receiverID, idType st
 		return fmt.Errorf("send message: %w", err)
 	}
 	if res.Code != 0 {
-		return fmt.Errorf(
-			"send failed with error code %d, please see "+
-				"https://open.larksuite.com/document/ukTMukTMukTM/ugjM14COyUjL4ITN for details",
-			res.Code,
-		)
+		return apiError(res.Code)
 	}
 
 	return nil
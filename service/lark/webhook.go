@@ -32,7 +32,11 @@ func NewWebhookService(webhookURL string) *WebhookService {
 
 // Send sends the message subject and body to the group chat.
 func (w *WebhookService) Send(_ context.Context, subject, message string) error {
-	return w.cli.Send(subject, message)
+	if err := w.cli.Send(subject, message); err != nil {
+		return newReceiverError("", err)
+	}
+
+	return nil
 }
 
 // larkClientGoLarkNotificationBot is a wrapper around go-lark/lark's Bot, to
@@ -53,11 +57,7 @@ func (w *larkClientGoLarkNotificationBot) Send(subject, message string) error {
 		return fmt.Errorf("post webhook message: %w", err)
 	}
 	if res.Code != 0 {
-		return fmt.Errorf(
-			"send failed with error code %d, please see "+
-				"https://open.larksuite.com/document/ukTMukTMukTM/ugjM14COyUjL4ITN for details",
-			res.Code,
-		)
+		return apiError(res.Code)
 	}
 	return nil
 }
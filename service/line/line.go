@@ -2,15 +2,19 @@ package line
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/line/line-bot-sdk-go/linebot"
+
+	"github.com/nikoksr/notify"
 )
 
 // Line struct holds info about client and destination ID for communicating with line API.
 type Line struct {
 	client      *linebot.Client
 	receiverIDs []string
+	delivery    notify.DeliveryOptions
 }
 
 // New creates a new instance of Line notifier service
@@ -34,6 +38,12 @@ func (l *Line) AddReceivers(receiverIDs ...string) {
 	l.receiverIDs = append(l.receiverIDs, receiverIDs...)
 }
 
+// SetDeliveryOptions configures how the Send method delivers a message to multiple receivers. By default, receivers are
+// processed sequentially and delivery stops at the first error.
+func (l *Line) SetDeliveryOptions(opts notify.DeliveryOptions) {
+	l.delivery = opts
+}
+
 // Send receives message subject and body then sends it to all receivers set previously
 // Subject will be on the first line followed by message on the next line.
 func (l *Line) Send(ctx context.Context, subject, message string) error {
@@ -41,17 +51,28 @@ func (l *Line) Send(ctx context.Context, subject, message string) error {
 		Text: subject + "\n" + message,
 	}
 
-	for _, receiverID := range l.receiverIDs {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			_, err := l.client.PushMessage(receiverID, lineMessage).WithContext(ctx).Do()
-			if err != nil {
-				return fmt.Errorf("push message to %q: %w", receiverID, err)
-			}
+	return notify.Deliver(ctx, l.delivery, l.receiverIDs, func(ctx context.Context, receiverID string) error {
+		_, err := l.client.PushMessage(receiverID, lineMessage).WithContext(ctx).Do()
+		if err != nil {
+			return newReceiverError(receiverID, fmt.Errorf("push message to %q: %w", receiverID, err))
+		}
+
+		return nil
+	})
+}
+
+// newReceiverError wraps the given error into a notify.ReceiverError and classifies it based on the HTTP status code
+// returned by the LINE Messaging API.
+func newReceiverError(receiverID string, err error) *notify.ReceiverError {
+	rErr := notify.NewReceiverError("line", receiverID, err)
+
+	var apiErr *linebot.APIError
+	if errors.As(err, &apiErr) {
+		rErr.SetStatusCode(apiErr.Code)
+		if apiErr.Response != nil {
+			rErr.Code = apiErr.Response.Message
 		}
 	}
 
-	return nil
+	return rErr
 }
@@ -2,9 +2,12 @@ package line
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/utahta/go-linenotify"
+
+	"github.com/nikoksr/notify"
 )
 
 // Notify holds info about client and destination token for communicating with line API.
@@ -41,7 +44,12 @@ func (ln *Notify) Send(ctx context.Context, subject, message string) error {
 		default:
 			_, err := ln.client.NotifyMessage(ctx, receiverToken, lineMessage)
 			if err != nil {
-				return fmt.Errorf("send message to %q: %w", receiverToken, err)
+				rErr := notify.NewReceiverError("line", receiverToken, fmt.Errorf("send message to %q: %w", receiverToken, err))
+				if errors.Is(err, linenotify.ErrNotifyInvalidAccessToken) {
+					rErr.SetKind(notify.ErrInvalidReceiver)
+				}
+
+				return rErr
 			}
 		}
 	}
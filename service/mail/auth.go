@@ -0,0 +1,125 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+type (
+	// loginAuth implements the LOGIN authentication mechanism.
+	loginAuth struct {
+		username string
+		password string
+		host     string
+	}
+
+	// xoauth2Auth implements the XOAUTH2 authentication mechanism.
+	xoauth2Auth struct {
+		username string
+		source   oauth2.TokenSource
+	}
+)
+
+// Compile-time checks to ensure the mechanisms implement smtp.Auth.
+var (
+	_ smtp.Auth = (*loginAuth)(nil)
+	_ smtp.Auth = (*xoauth2Auth)(nil)
+)
+
+// LoginAuth returns an smtp.Auth that implements the LOGIN authentication mechanism, which is required by some servers
+// that don't support PLAIN, e.g. Microsoft Exchange. Like smtp.PlainAuth, it only sends the credentials over TLS
+// connections, or to localhost, and if the server name matches the given host.
+func LoginAuth(username, password, host string) smtp.Auth {
+	return &loginAuth{username: username, password: password, host: host}
+}
+
+// XOAuth2Auth returns an smtp.Auth that implements the XOAUTH2 authentication mechanism, which is required by Gmail
+// and Microsoft 365. The access tokens are obtained from the given token source, e.g. an oauth2.Config; they are only
+// sent over TLS connections, or to localhost. Use oauth2.ReuseTokenSource to cache the tokens.
+func XOAuth2Auth(username string, source oauth2.TokenSource) smtp.Auth {
+	return &xoauth2Auth{username: username, source: source}
+}
+
+// isLocalhost reports whether the given server name refers to the local machine.
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
+
+// Start begins the authentication with the server.
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+
+	return "LOGIN", nil, nil
+}
+
+// Next answers the username and password challenges of the server.
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+
+	switch challenge := strings.ToLower(strings.TrimSpace(string(fromServer))); {
+	case strings.HasPrefix(challenge, "username"):
+		return []byte(a.username), nil
+	case strings.HasPrefix(challenge, "password"):
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected LOGIN challenge %q", fromServer)
+	}
+}
+
+// Start begins the authentication with the server, using a valid access token.
+func (a *xoauth2Auth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+
+	token, err := a.source.Token()
+	if err != nil {
+		return "", nil, fmt.Errorf("fetch oauth2 token: %w", err)
+	}
+
+	return "XOAUTH2", []byte("user=" + a.username + "\x01auth=Bearer " + token.AccessToken + "\x01\x01"), nil
+}
+
+// Next acknowledges the error details that the server sends if the token was rejected, so that the server completes
+// the exchange with an error reply.
+func (a *xoauth2Auth) Next(_ []byte, more bool) ([]byte, error) {
+	if more {
+		return []byte{}, nil
+	}
+
+	return nil, nil
+}
+
+// AuthenticateLogin authenticates you to send emails via smtp using the LOGIN mechanism.
+// Example values: "test@outlook.com", "password123", "smtp.office365.com"
+func (m *Mail) AuthenticateLogin(username, password, host string) {
+	m.smtpAuth = LoginAuth(username, password, host)
+}
+
+// AuthenticateCRAMMD5 authenticates you to send emails via smtp using the CRAM-MD5 mechanism, which doesn't send the
+// secret to the server.
+func (m *Mail) AuthenticateCRAMMD5(username, secret string) {
+	m.smtpAuth = smtp.CRAMMD5Auth(username, secret)
+}
+
+// AuthenticateXOAUTH2 authenticates you to send emails via smtp using the XOAUTH2 mechanism, with access tokens from the
+// given token source. The tokens are cached until they expire.
+func (m *Mail) AuthenticateXOAUTH2(username string, source oauth2.TokenSource) {
+	m.smtpAuth = XOAuth2Auth(username, oauth2.ReuseTokenSource(nil, source))
+}
+
+// SetSMTPAuth sets a custom authentication mechanism, e.g. one returned by LoginAuth or XOAuth2Auth.
+func (m *Mail) SetSMTPAuth(auth smtp.Auth) {
+	m.smtpAuth = auth
+}
@@ -2,20 +2,39 @@ package mail
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	netmail "net/mail"
 	"net/smtp"
 	"net/textproto"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/jordan-wright/email"
+
+	"github.com/nikoksr/notify"
+	"github.com/nikoksr/notify/internal/htmltext"
 )
 
 // Mail struct holds necessary data to send emails.
 type Mail struct {
 	usePlainText      bool
+	generatePlainText bool
 	senderAddress     string
+	senderName        string
 	smtpHostAddr      string
 	smtpAuth          smtp.Auth
+	tlsMode           TLSMode
+	tlsConfig         *tls.Config
+	pool              *connPool
 	receiverAddresses []string
+	ccAddresses       []string
+	bccAddresses      []string
+	replyToAddresses  []string
+	headers           textproto.MIMEHeader
+	perRecipient      bool
 }
 
 // New returns a new instance of a Mail notification service.
@@ -25,6 +44,7 @@ func New(senderAddress, smtpHostAddress string) *Mail {
 		senderAddress:     senderAddress,
 		smtpHostAddr:      smtpHostAddress,
 		receiverAddresses: []string{},
+		headers:           textproto.MIMEHeader{},
 	}
 }
 
@@ -36,13 +56,18 @@ const (
 	PlainText BodyType = iota
 	// HTML is used to specify that the body is HTML.
 	HTML
+	// Alternative is used to specify that the body is HTML, which is sent together with a plain-text version that is
+	// generated from it.
+	Alternative
 )
 
-// AuthenticateSMTP authenticates you to send emails via smtp.
+// AuthenticateSMTP authenticates you to send emails via smtp using the PLAIN mechanism.
 // Example values: "", "test@gmail.com", "password123", "smtp.gmail.com"
 // For more information about smtp authentication, see here:
 //
 //	-> https://pkg.go.dev/net/smtp#PlainAuth
+//
+// See AuthenticateLogin, AuthenticateCRAMMD5, and AuthenticateXOAUTH2 for other mechanisms.
 func (m *Mail) AuthenticateSMTP(identity, userName, password, host string) {
 	m.smtpAuth = smtp.PlainAuth(identity, userName, password, host)
 }
@@ -53,23 +78,74 @@ func (m *Mail) AddReceivers(addresses ...string) {
 	m.receiverAddresses = append(m.receiverAddresses, addresses...)
 }
 
+// AddCC takes email addresses and adds them to the list of carbon copy receivers. They are visible to all receivers.
+func (m *Mail) AddCC(addresses ...string) {
+	m.ccAddresses = append(m.ccAddresses, addresses...)
+}
+
+// AddBCC takes email addresses and adds them to the list of blind carbon copy receivers. They receive every message,
+// but are not visible to the other receivers.
+func (m *Mail) AddBCC(addresses ...string) {
+	m.bccAddresses = append(m.bccAddresses, addresses...)
+}
+
+// SetReplyTo sets the addresses that replies should be sent to, instead of the sender address.
+func (m *Mail) SetReplyTo(addresses ...string) {
+	m.replyToAddresses = addresses
+}
+
+// SetSenderName sets the display name of the sender, e.g. "Build Server". The name is encoded as needed.
+func (m *Mail) SetSenderName(name string) {
+	m.senderName = name
+}
+
+// SetHeader sets a custom header that is added to every message, e.g. "List-Unsubscribe". It replaces any existing
+// values of the header, including X-Priority and Importance, which are otherwise set from the context.
+func (m *Mail) SetHeader(key, value string) {
+	m.headers.Set(key, value)
+}
+
+// SendPerRecipient can be used to send a separate message to every receiver, including the CC and BCC receivers, so
+// that they can't see each other's addresses. Each message is addressed to its receiver only. Default is false.
+func (m *Mail) SendPerRecipient(enabled bool) {
+	m.perRecipient = enabled
+}
+
 // BodyFormat can be used to specify the format of the body.
 // Default BodyType is HTML.
 func (m *Mail) BodyFormat(format BodyType) {
 	switch format {
 	case PlainText:
 		m.usePlainText = true
+		m.generatePlainText = false
 	case HTML:
 		m.usePlainText = false
+		m.generatePlainText = false
+	case Alternative:
+		m.usePlainText = false
+		m.generatePlainText = true
 	default:
 		m.usePlainText = false
+		m.generatePlainText = false
+	}
+}
+
+// sender returns the From address of the messages, including the display name of the sender, if any.
+func (m *Mail) sender() string {
+	if m.senderName == "" {
+		return m.senderAddress
 	}
+
+	return (&netmail.Address{Name: m.senderName, Address: m.senderAddress}).String()
 }
 
 func (m *Mail) newEmail(subject, message string) *email.Email {
 	msg := &email.Email{
 		To:      m.receiverAddresses,
-		From:    m.senderAddress,
+		Cc:      m.ccAddresses,
+		Bcc:     m.bccAddresses,
+		ReplyTo: m.replyToAddresses,
+		From:    m.sender(),
 		Subject: subject,
 		Headers: textproto.MIMEHeader{},
 	}
@@ -82,20 +158,160 @@ func (m *Mail) newEmail(subject, message string) *email.Email {
 	return msg
 }
 
+// addPlainText adds the plain-text alternative to the given HTML message, either from the given context or generated
+// from the HTML if the body format is Alternative.
+func (m *Mail) addPlainText(ctx context.Context, msg *email.Email) {
+	if msg.HTML == nil || msg.Text != nil {
+		return
+	}
+
+	if text, ok := notify.PlainTextFromContext(ctx); ok {
+		msg.Text = []byte(text)
+	} else if m.generatePlainText {
+		msg.Text = []byte(htmltext.Convert(string(msg.HTML)))
+	}
+}
+
+// setPriorityHeaders translates the notify.Priority carried by the given context into the X-Priority and Importance
+// headers, which are understood by most mail clients. Nothing is set if the context carries no priority.
+func setPriorityHeaders(ctx context.Context, header textproto.MIMEHeader) {
+	priority, ok := notify.PriorityFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	switch {
+	case priority <= notify.PriorityMin:
+		header.Set("X-Priority", "5 (Lowest)")
+		header.Set("Importance", "low")
+	case priority == notify.PriorityLow:
+		header.Set("X-Priority", "4 (Low)")
+		header.Set("Importance", "low")
+	case priority == notify.PriorityDefault:
+		header.Set("X-Priority", "3 (Normal)")
+		header.Set("Importance", "normal")
+	case priority == notify.PriorityHigh:
+		header.Set("X-Priority", "2 (High)")
+		header.Set("Importance", "high")
+	default:
+		header.Set("X-Priority", "1 (Highest)")
+		header.Set("Importance", "high")
+	}
+}
+
+// newEmails returns the messages that are sent for the given subject and message: either a single message to all
+// receivers, or one message per receiver if SendPerRecipient is enabled.
+func (m *Mail) newEmails(subject, message string) []*email.Email {
+	if !m.perRecipient {
+		return []*email.Email{m.newEmail(subject, message)}
+	}
+
+	receivers := slices.Concat(m.receiverAddresses, m.ccAddresses, m.bccAddresses)
+	msgs := make([]*email.Email, 0, len(receivers))
+	for _, receiver := range receivers {
+		msg := m.newEmail(subject, message)
+		msg.To = []string{receiver}
+		msg.Cc = nil
+		msg.Bcc = nil
+		msgs = append(msgs, msg)
+	}
+
+	return msgs
+}
+
 // Send takes a message subject and a message body and sends them to all previously set chats. Message body supports
-// html as markup language.
+// html as markup language. The notify.Priority carried by the context, if any, is set as X-Priority and Importance
+// header, unless these headers were set using SetHeader.
+//
+// HTML messages are sent as multipart/alternative together with the plain text carried by the context, see
+// notify.WithPlainText. Without it, the plain text is generated from the HTML if the body format is Alternative.
+//
+// If SendPerRecipient is enabled, a failed message doesn't stop the others from being sent; all errors are returned
+// together.
 func (m Mail) Send(ctx context.Context, subject, message string) error {
-	msg := m.newEmail(subject, message)
+	var errs []error
+	for _, msg := range m.newEmails(subject, message) {
+		if err := m.send(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if len(errs) == 1 {
+		return errs[0]
+	}
+
+	return errors.Join(errs...)
+}
+
+// send sends a single message.
+func (m *Mail) send(ctx context.Context, msg *email.Email) error {
+	m.addPlainText(ctx, msg)
+	setPriorityHeaders(ctx, msg.Headers)
+	for key, values := range m.headers {
+		msg.Headers[key] = slices.Clone(values)
+	}
 
-	var err error
 	select {
 	case <-ctx.Done():
-		err = ctx.Err()
+		return ctx.Err()
 	default:
-		if err = msg.Send(m.smtpHostAddr, m.smtpAuth); err != nil {
-			err = fmt.Errorf("send email: %w", err)
-		}
 	}
 
-	return err
+	if err := m.sendMail(ctx, msg); err != nil {
+		receivers := slices.Concat(msg.To, msg.Cc, msg.Bcc)
+		return newReceiverError(receivers, fmt.Errorf("send email: %w", err))
+	}
+
+	return nil
+}
+
+// Check verifies the connection to the SMTP server and the configured credentials. It connects to the server, secures
+// the connection according to the TLS mode, authenticates if credentials were set, and finishes with a NOOP command. It
+// implements the notify.Checker interface.
+func (m Mail) Check(ctx context.Context) error {
+	client, err := m.dial(ctx)
+	if err != nil {
+		return newReceiverError(nil, err)
+	}
+	defer func() { _ = client.Close() }()
+
+	// Abort any pending command once the context is done.
+	stop := context.AfterFunc(ctx, func() { _ = client.Close() })
+	defer stop()
+
+	if err = client.Noop(); err != nil {
+		return newReceiverError(nil, fmt.Errorf("NOOP: %w", err))
+	}
+
+	return client.Quit()
+}
+
+// newReceiverError wraps the given error into a notify.ReceiverError and classifies it based on the SMTP reply code, if
+// the server sent one. See RFC 5321, section 4.2.
+func newReceiverError(receiverAddresses []string, err error) *notify.ReceiverError {
+	rErr := notify.NewReceiverError("mail", strings.Join(receiverAddresses, ","), err)
+
+	var smtpErr *textproto.Error
+	if !errors.As(err, &smtpErr) {
+		return rErr
+	}
+
+	rErr.Code = strconv.Itoa(smtpErr.Code)
+	switch smtpErr.Code {
+	case 530, 535: // Authentication required or credentials invalid
+		rErr.SetKind(notify.ErrAuthFailure)
+	case 550, 551, 553: // Mailbox unavailable or name not allowed
+		rErr.SetKind(notify.ErrInvalidReceiver)
+	case 552: // Exceeded storage allocation, also used for oversized messages
+		rErr.SetKind(notify.ErrPayloadTooLarge)
+	case 421, 450, 451: // Temporary failures
+		rErr.Retryable = true
+	case 452: // Too many recipients, usually a rate limit
+		rErr.SetKind(notify.ErrRateLimited)
+	}
+
+	return rErr
 }
@@ -1,11 +1,234 @@
 package mail
 
 import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"net"
+	"net/http/httptest"
+	netmail "net/mail"
+	"net/smtp"
+	"net/textproto"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/nikoksr/notify"
 )
 
+// canceledContext returns a context that is already canceled, so that sends stop before connecting to the server.
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	return ctx
+}
+
+// smtpMessage is a message received by a fake SMTP server.
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+	tls  bool
+}
+
+// smtpServerOptions configure a fake SMTP server.
+type smtpServerOptions struct {
+	rejected    []string    // Receivers that are rejected with a 550 reply.
+	tlsConfig   *tls.Config // Enables STARTTLS, or implicit TLS if implicitTLS is set.
+	implicitTLS bool
+	username    string // Enables the LOGIN, CRAM-MD5, and XOAUTH2 mechanisms.
+	password    string // Also the secret for CRAM-MD5 and the access token for XOAUTH2.
+}
+
+// smtpServer is a fake SMTP server.
+type smtpServer struct {
+	opts        smtpServerOptions
+	addr        string
+	messages    chan smtpMessage
+	connections atomic.Int32
+	stalled     atomic.Bool // Stops replying to commands, e.g. to simulate an unresponsive server.
+}
+
+// newSMTPServer starts a fake SMTP server that accepts every message and returns its address and a channel that
+// receives the messages. Receivers in the given set are rejected with a 550 reply.
+func newSMTPServer(t *testing.T, rejected ...string) (string, <-chan smtpMessage) {
+	t.Helper()
+
+	server := startSMTPServer(t, smtpServerOptions{rejected: rejected})
+
+	return server.addr, server.messages
+}
+
+// startSMTPServer starts a fake SMTP server with the given options.
+func startSMTPServer(t *testing.T, opts smtpServerOptions) *smtpServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	server := &smtpServer{opts: opts, addr: listener.Addr().String(), messages: make(chan smtpMessage, 10)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			server.connections.Add(1)
+
+			if opts.implicitTLS {
+				conn = tls.Server(conn, opts.tlsConfig)
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+// serve handles a single connection of the fake SMTP server.
+func (s *smtpServer) serve(netConn net.Conn) {
+	conn := textproto.NewConn(netConn)
+	defer func() { _ = conn.Close() }()
+
+	_ = conn.PrintfLine("220 localhost ESMTP")
+
+	secure := s.opts.implicitTLS
+	var msg smtpMessage
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+		if s.stalled.Load() {
+			continue
+		}
+
+		command, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(command) {
+		case "EHLO", "HELO":
+			extensions := []string{"localhost"}
+			if s.opts.tlsConfig != nil && !secure {
+				extensions = append(extensions, "STARTTLS")
+			}
+			if s.opts.username != "" {
+				extensions = append(extensions, "AUTH LOGIN CRAM-MD5 XOAUTH2")
+			}
+			for i, extension := range extensions {
+				separator := "-"
+				if i == len(extensions)-1 {
+					separator = " "
+				}
+				_ = conn.PrintfLine("250%s%s", separator, extension)
+			}
+		case "STARTTLS":
+			_ = conn.PrintfLine("220 ready to start TLS")
+			tlsConn := tls.Server(netConn, s.opts.tlsConfig)
+			if err = tlsConn.Handshake(); err != nil {
+				return
+			}
+			netConn, conn, secure = tlsConn, textproto.NewConn(tlsConn), true
+		case "AUTH":
+			if s.authenticate(conn, arg) {
+				_ = conn.PrintfLine("235 authenticated")
+			} else {
+				_ = conn.PrintfLine("535 authentication failed")
+			}
+		case "MAIL":
+			msg = smtpMessage{from: strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>"), tls: secure}
+			_ = conn.PrintfLine("250 OK")
+		case "RCPT":
+			receiver := strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>")
+			if slices.Contains(s.opts.rejected, receiver) {
+				_ = conn.PrintfLine("550 mailbox unavailable")
+				continue
+			}
+			msg.to = append(msg.to, receiver)
+			_ = conn.PrintfLine("250 OK")
+		case "DATA":
+			_ = conn.PrintfLine("354 go ahead")
+			data, err := conn.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg.data = string(data)
+			s.messages <- msg
+			_ = conn.PrintfLine("250 OK")
+		case "QUIT":
+			_ = conn.PrintfLine("221 bye")
+			return
+		default:
+			_ = conn.PrintfLine("250 OK")
+		}
+	}
+}
+
+// authenticate runs the exchange of the given AUTH command and reports whether the client sent valid credentials.
+func (s *smtpServer) authenticate(conn *textproto.Conn, arg string) bool {
+	challenge := func(text string) string {
+		_ = conn.PrintfLine("334 %s", base64.StdEncoding.EncodeToString([]byte(text)))
+		line, _ := conn.ReadLine()
+		answer, _ := base64.StdEncoding.DecodeString(line)
+
+		return string(answer)
+	}
+
+	mechanism, initial, _ := strings.Cut(arg, " ")
+	switch mechanism {
+	case "LOGIN":
+		username := challenge("Username:")
+		password := challenge("Password:")
+
+		return username == s.opts.username && password == s.opts.password
+	case "CRAM-MD5":
+		const nonce = "<1896.697170952@localhost>"
+		username, digest, _ := strings.Cut(challenge(nonce), " ")
+
+		mac := hmac.New(md5.New, []byte(s.opts.password))
+		mac.Write([]byte(nonce))
+
+		return username == s.opts.username && digest == hex.EncodeToString(mac.Sum(nil))
+	case "XOAUTH2":
+		response, _ := base64.StdEncoding.DecodeString(initial)
+		if string(response) == "user="+s.opts.username+"\x01auth=Bearer "+s.opts.password+"\x01\x01" {
+			return true
+		}
+
+		// Invalid tokens are answered with error details, which the client must acknowledge.
+		challenge(`{"status":"401","schemes":"bearer"}`)
+
+		return false
+	}
+
+	return false
+}
+
+// newTestTLSConfig returns the TLS configuration of a server with a certificate for 127.0.0.1, and a pool that trusts
+// the certificate.
+func newTestTLSConfig(t *testing.T) (*tls.Config, *x509.CertPool) {
+	t.Helper()
+
+	server := httptest.NewTLSServer(nil)
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	return server.TLS.Clone(), roots
+}
+
 func TestMail_newEmailHtml(t *testing.T) {
 	t.Parallel()
 
@@ -50,3 +273,347 @@ func TestMail_AuthenticateSMTP(t *testing.T) {
 	m.AuthenticateSMTP("test", "test", "test", "test")
 	assert.NotNil(t, m.smtpAuth)
 }
+
+func TestMail_setPriorityHeaders(t *testing.T) {
+	t.Parallel()
+
+	m := New("foo", "server")
+
+	email := m.newEmail("test", "test")
+	setPriorityHeaders(context.Background(), email.Headers)
+	assert.Empty(t, email.Headers)
+
+	email = m.newEmail("test", "test")
+	setPriorityHeaders(notify.WithPriority(context.Background(), notify.PriorityUrgent), email.Headers)
+	assert.Equal(t, "1 (Highest)", email.Headers.Get("X-Priority"))
+	assert.Equal(t, "high", email.Headers.Get("Importance"))
+
+	email = m.newEmail("test", "test")
+	setPriorityHeaders(notify.WithSeverity(context.Background(), notify.SeverityDebug), email.Headers)
+	assert.Equal(t, "5 (Lowest)", email.Headers.Get("X-Priority"))
+	assert.Equal(t, "low", email.Headers.Get("Importance"))
+}
+
+func TestMail_newEmailHeaders(t *testing.T) {
+	t.Parallel()
+
+	m := New("alerts@example.com", "server")
+	m.AddReceivers("a@example.com")
+	m.AddCC("b@example.com")
+	m.AddBCC("c@example.com")
+	m.SetReplyTo("ops@example.com")
+	m.SetSenderName("Build Server")
+	m.SetHeader("List-Unsubscribe", "<mailto:unsubscribe@example.com>")
+
+	email := m.newEmail("test", "test")
+	require.ErrorIs(t, m.send(canceledContext(), email), context.Canceled)
+
+	raw, err := email.Bytes()
+	require.NoError(t, err)
+
+	msg, err := netmail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+	assert.Equal(t, `"Build Server" <alerts@example.com>`, msg.Header.Get("From"))
+	assert.Equal(t, "<a@example.com>", msg.Header.Get("To"))
+	assert.Equal(t, "<b@example.com>", msg.Header.Get("Cc"))
+	assert.Equal(t, "ops@example.com", msg.Header.Get("Reply-To"))
+	assert.Equal(t, "<mailto:unsubscribe@example.com>", msg.Header.Get("List-Unsubscribe"))
+	assert.Empty(t, msg.Header.Get("Bcc"), "BCC receivers must not be visible")
+}
+
+func TestMail_SetHeaderOverridesPriority(t *testing.T) {
+	t.Parallel()
+
+	m := New("foo", "server")
+	m.SetHeader("X-Priority", "3")
+
+	email := m.newEmail("test", "test")
+	_ = m.send(notify.WithPriority(canceledContext(), notify.PriorityUrgent), email)
+	assert.Equal(t, "3", email.Headers.Get("X-Priority"))
+	assert.Equal(t, "high", email.Headers.Get("Importance"))
+}
+
+func TestMail_newEmails(t *testing.T) {
+	t.Parallel()
+
+	m := New("foo", "server")
+	m.AddReceivers("a@example.com", "b@example.com")
+	m.AddCC("c@example.com")
+	m.AddBCC("d@example.com")
+
+	emails := m.newEmails("test", "test")
+	require.Len(t, emails, 1)
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, emails[0].To)
+	assert.Equal(t, []string{"c@example.com"}, emails[0].Cc)
+	assert.Equal(t, []string{"d@example.com"}, emails[0].Bcc)
+
+	m.SendPerRecipient(true)
+	emails = m.newEmails("test", "test")
+	require.Len(t, emails, 4)
+	for i, receiver := range []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com"} {
+		assert.Equal(t, []string{receiver}, emails[i].To)
+		assert.Empty(t, emails[i].Cc)
+		assert.Empty(t, emails[i].Bcc)
+	}
+}
+
+func TestMail_SendPerRecipient(t *testing.T) {
+	t.Parallel()
+
+	addr, messages := newSMTPServer(t, "rejected@example.com")
+
+	m := New("alerts@example.com", addr)
+	m.AddReceivers("a@example.com", "rejected@example.com")
+	m.AddBCC("b@example.com")
+	m.SendPerRecipient(true)
+
+	err := m.Send(context.Background(), "test", "test")
+
+	var rErr *notify.ReceiverError
+	require.ErrorAs(t, err, &rErr)
+	assert.Equal(t, "rejected@example.com", rErr.Receiver)
+	assert.ErrorIs(t, err, notify.ErrInvalidReceiver)
+
+	// The rejected receiver doesn't stop the others from receiving their message.
+	for _, receiver := range []string{"a@example.com", "b@example.com"} {
+		msg := <-messages
+		assert.Equal(t, "alerts@example.com", msg.from)
+		assert.Equal(t, []string{receiver}, msg.to)
+		assert.Contains(t, msg.data, "To: <"+receiver+">")
+	}
+}
+
+func TestMail_SendAlternative(t *testing.T) {
+	t.Parallel()
+
+	addr, messages := newSMTPServer(t)
+
+	m := New("alerts@example.com", addr)
+	m.AddReceivers("a@example.com")
+
+	// HTML only, without a plain-text alternative.
+	require.NoError(t, m.Send(context.Background(), "test", "<p>Disk <b>full</b></p>"))
+	msg := <-messages
+	assert.Contains(t, msg.data, "Content-Type: text/html")
+	assert.NotContains(t, msg.data, "multipart/alternative")
+
+	// The plain text is taken from the context.
+	ctx := notify.WithPlainText(context.Background(), "Disk is full")
+	require.NoError(t, m.Send(ctx, "test", "<p>Disk <b>full</b></p>"))
+	msg = <-messages
+	assert.Contains(t, msg.data, "Content-Type: multipart/alternative")
+	assert.Contains(t, msg.data, "Disk is full")
+
+	// The plain text is generated from the HTML.
+	m.BodyFormat(Alternative)
+	require.NoError(t, m.Send(context.Background(), "test", "<p>Disk <b>full</b></p>"))
+	msg = <-messages
+	assert.Contains(t, msg.data, "Content-Type: multipart/alternative")
+	assert.Contains(t, msg.data, "Content-Type: text/plain")
+	assert.Contains(t, msg.data, "\nDisk full\n")
+
+	// Plain-text messages stay as they are.
+	m.BodyFormat(PlainText)
+	require.NoError(t, m.Send(ctx, "test", "Disk full"))
+	msg = <-messages
+	assert.NotContains(t, msg.data, "multipart/alternative")
+	assert.NotContains(t, msg.data, "Disk is full")
+}
+
+func TestMail_TLSModes(t *testing.T) {
+	t.Parallel()
+
+	tlsConfig, roots := newTestTLSConfig(t)
+
+	tests := []struct {
+		name      string
+		server    smtpServerOptions
+		mode      TLSMode
+		roots     *x509.CertPool
+		wantTLS   bool
+		wantError string
+	}{
+		{
+			name:    "Opportunistic with STARTTLS",
+			server:  smtpServerOptions{tlsConfig: tlsConfig},
+			mode:    TLSOpportunistic,
+			roots:   roots,
+			wantTLS: true,
+		},
+		{
+			name: "Opportunistic without STARTTLS",
+			mode: TLSOpportunistic,
+		},
+		{
+			name:      "Opportunistic with untrusted certificate",
+			server:    smtpServerOptions{tlsConfig: tlsConfig},
+			mode:      TLSOpportunistic,
+			wantError: "certificate",
+		},
+		{
+			name:    "Mandatory with STARTTLS",
+			server:  smtpServerOptions{tlsConfig: tlsConfig},
+			mode:    TLSMandatory,
+			roots:   roots,
+			wantTLS: true,
+		},
+		{
+			name:      "Mandatory without STARTTLS",
+			mode:      TLSMandatory,
+			wantError: "doesn't support STARTTLS",
+		},
+		{
+			name:    "Implicit",
+			server:  smtpServerOptions{tlsConfig: tlsConfig, implicitTLS: true},
+			mode:    TLSImplicit,
+			roots:   roots,
+			wantTLS: true,
+		},
+		{
+			name:   "None",
+			server: smtpServerOptions{tlsConfig: tlsConfig},
+			mode:   TLSNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := startSMTPServer(t, tt.server)
+
+			m := New("alerts@example.com", server.addr)
+			m.AddReceivers("a@example.com")
+			m.SetTLSMode(tt.mode)
+			if tt.roots != nil {
+				m.SetTLSConfig(&tls.Config{RootCAs: tt.roots, MinVersion: tls.VersionTLS12})
+			}
+
+			err := m.Send(context.Background(), "test", "test")
+			if tt.wantError != "" {
+				require.ErrorContains(t, err, tt.wantError)
+				require.ErrorContains(t, m.Check(context.Background()), tt.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTLS, (<-server.messages).tls)
+			require.NoError(t, m.Check(context.Background()))
+		})
+	}
+}
+
+func TestMail_Authentication(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		authenticate func(m *Mail, password string)
+	}{
+		{
+			name:         "LOGIN",
+			authenticate: func(m *Mail, password string) { m.AuthenticateLogin("user", password, "127.0.0.1") },
+		},
+		{
+			name:         "CRAM-MD5",
+			authenticate: func(m *Mail, password string) { m.AuthenticateCRAMMD5("user", password) },
+		},
+		{
+			name: "XOAUTH2",
+			authenticate: func(m *Mail, password string) {
+				m.AuthenticateXOAUTH2("user", oauth2.StaticTokenSource(&oauth2.Token{AccessToken: password}))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := startSMTPServer(t, smtpServerOptions{username: "user", password: "secret"})
+
+			m := New("alerts@example.com", server.addr)
+			m.AddReceivers("a@example.com")
+
+			tt.authenticate(m, "secret")
+			require.NoError(t, m.Send(context.Background(), "test", "test"))
+			assert.Equal(t, []string{"a@example.com"}, (<-server.messages).to)
+
+			tt.authenticate(m, "wrong")
+			err := m.Send(context.Background(), "test", "test")
+			require.ErrorIs(t, err, notify.ErrAuthFailure)
+			require.ErrorIs(t, m.Check(context.Background()), notify.ErrAuthFailure)
+		})
+	}
+}
+
+func TestAuth_UnencryptedConnection(t *testing.T) {
+	t.Parallel()
+
+	server := &smtp.ServerInfo{Name: "smtp.example.com", TLS: false}
+	token := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token"})
+
+	_, _, err := LoginAuth("user", "password", "smtp.example.com").Start(server)
+	require.ErrorContains(t, err, "unencrypted connection")
+
+	_, _, err = XOAuth2Auth("user", token).Start(server)
+	require.ErrorContains(t, err, "unencrypted connection")
+
+	server.TLS = true
+	_, _, err = LoginAuth("user", "password", "other.example.com").Start(server)
+	require.ErrorContains(t, err, "wrong host name")
+
+	mechanism, response, err := XOAuth2Auth("user", token).Start(server)
+	require.NoError(t, err)
+	assert.Equal(t, "XOAUTH2", mechanism)
+	assert.Equal(t, "user=user\x01auth=Bearer token\x01\x01", string(response))
+}
+
+func TestMail_ConnectionPool(t *testing.T) {
+	t.Parallel()
+
+	server := startSMTPServer(t, smtpServerOptions{rejected: []string{"rejected@example.com"}})
+
+	now := time.Now()
+	m := New("alerts@example.com", server.addr)
+	m.AddReceivers("a@example.com")
+	m.EnableConnectionPool(2, time.Minute)
+	m.pool.now = func() time.Time { return now }
+
+	// Messages are sent over the same connection.
+	for range 3 {
+		require.NoError(t, m.Send(context.Background(), "test", "test"))
+		<-server.messages
+	}
+	assert.Equal(t, int32(1), server.connections.Load())
+
+	// Failed transactions don't break the connection.
+	m.AddBCC("rejected@example.com")
+	require.ErrorIs(t, m.Send(context.Background(), "test", "test"), notify.ErrInvalidReceiver)
+	m.bccAddresses = nil
+	require.NoError(t, m.Send(context.Background(), "test", "test"))
+	<-server.messages
+	assert.Equal(t, int32(1), server.connections.Load())
+
+	// Idle connections expire.
+	now = now.Add(2 * time.Minute)
+	require.NoError(t, m.Send(context.Background(), "test", "test"))
+	<-server.messages
+	assert.Equal(t, int32(2), server.connections.Load())
+
+	// Closed connections are replaced.
+	require.NoError(t, m.Close())
+	require.NoError(t, m.Send(context.Background(), "test", "test"))
+	<-server.messages
+	assert.Equal(t, int32(3), server.connections.Load())
+	require.NoError(t, m.Close())
+
+	// Checking an idle connection is aborted once the context is done.
+	require.NoError(t, m.Send(context.Background(), "test", "test"))
+	<-server.messages
+	server.stalled.Store(true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, m.Send(ctx, "test", "test"), context.DeadlineExceeded)
+	assert.Equal(t, int32(4), server.connections.Load())
+}
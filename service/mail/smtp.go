@@ -0,0 +1,319 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jordan-wright/email"
+)
+
+// TLSMode is used to specify how connections to the SMTP server are secured.
+type TLSMode int
+
+const (
+	// TLSOpportunistic is used to specify that connections are upgraded using STARTTLS if the server supports it. This
+	// is the default.
+	TLSOpportunistic TLSMode = iota
+	// TLSMandatory is used to specify that connections must be upgraded using STARTTLS. Sending fails if the server
+	// doesn't support it, instead of falling back to an unencrypted connection.
+	TLSMandatory
+	// TLSImplicit is used to specify that connections use TLS right away, usually on port 465.
+	TLSImplicit
+	// TLSNone is used to specify that connections are never encrypted. Use it only for servers in trusted networks.
+	TLSNone
+)
+
+// smtpLocalName is the host name that is sent to the SMTP server with the EHLO command.
+const smtpLocalName = "localhost"
+
+// errSTARTTLSUnsupported is returned if TLSMandatory is used with a server that doesn't support STARTTLS.
+var errSTARTTLSUnsupported = errors.New("server doesn't support STARTTLS")
+
+// SetTLSMode can be used to specify how connections to the SMTP server are secured.
+// Default TLSMode is TLSOpportunistic.
+func (m *Mail) SetTLSMode(mode TLSMode) {
+	m.tlsMode = mode
+}
+
+// SetTLSConfig sets the TLS configuration that is used for STARTTLS and implicit TLS, e.g. to trust a private CA or to
+// present a client certificate. The configuration is cloned; its ServerName defaults to the host of the SMTP server.
+func (m *Mail) SetTLSConfig(config *tls.Config) {
+	m.tlsConfig = config
+}
+
+// EnableConnectionPool keeps up to maxIdle connections to the SMTP server open after sending, and reuses them for
+// subsequent messages, instead of connecting for every message. Idle connections are closed after idleTimeout, or when
+// the server closes them. Call Close to close the idle connections once the service is no longer used.
+func (m *Mail) EnableConnectionPool(maxIdle int, idleTimeout time.Duration) {
+	m.pool = &connPool{maxIdle: maxIdle, idleTimeout: idleTimeout, now: time.Now}
+}
+
+// Close closes the idle connections of the connection pool, if any.
+func (m *Mail) Close() error {
+	if m.pool == nil {
+		return nil
+	}
+
+	return m.pool.close()
+}
+
+// newTLSConfig returns the TLS configuration for connections to the given host.
+func (m *Mail) newTLSConfig(host string) *tls.Config {
+	if m.tlsConfig == nil {
+		return &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+	}
+
+	config := m.tlsConfig.Clone()
+	if config.ServerName == "" {
+		config.ServerName = host
+	}
+
+	return config
+}
+
+// dial connects to the SMTP server and returns a client that is ready to send messages: the connection is secured
+// according to the TLS mode, and authenticated if credentials were set.
+func (m *Mail) dial(ctx context.Context) (*smtp.Client, error) {
+	host, _, err := net.SplitHostPort(m.smtpHostAddr)
+	if err != nil {
+		return nil, fmt.Errorf("parse SMTP host address: %w", err)
+	}
+	tlsConfig := m.newTLSConfig(host)
+
+	var conn net.Conn
+	if m.tlsMode == TLSImplicit {
+		dialer := &tls.Dialer{Config: tlsConfig}
+		conn, err = dialer.DialContext(ctx, "tcp", m.smtpHostAddr)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", m.smtpHostAddr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dial SMTP server: %w", err)
+	}
+
+	// Abort any pending command once the context is done.
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("greet SMTP server: %w", err)
+	}
+
+	if err = m.handshake(client, tlsConfig); err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// handshake greets the server, upgrades the connection using STARTTLS as required by the TLS mode, and authenticates if
+// credentials were set.
+func (m *Mail) handshake(client *smtp.Client, tlsConfig *tls.Config) error {
+	if err := client.Hello(smtpLocalName); err != nil {
+		return fmt.Errorf("EHLO: %w", err)
+	}
+
+	if m.tlsMode == TLSOpportunistic || m.tlsMode == TLSMandatory {
+		ok, _ := client.Extension("STARTTLS")
+		if !ok && m.tlsMode == TLSMandatory {
+			return fmt.Errorf("STARTTLS: %w", errSTARTTLSUnsupported)
+		}
+
+		if ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("STARTTLS: %w", err)
+			}
+		}
+	}
+
+	if m.smtpAuth != nil {
+		if err := client.Auth(m.smtpAuth); err != nil {
+			return fmt.Errorf("AUTH: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// connect returns a client that is ready to send messages, either an idle one from the connection pool or a new one.
+func (m *Mail) connect(ctx context.Context) (*smtp.Client, error) {
+	if m.pool != nil {
+		for client := m.pool.get(); client != nil; client = m.pool.get() {
+			// The server may have closed the connection in the meantime. Abort the check once the context is done.
+			stop := context.AfterFunc(ctx, func() { _ = client.Close() })
+			err := client.Noop()
+			if !stop() {
+				return nil, fmt.Errorf("check pooled SMTP connection: %w", ctx.Err())
+			}
+
+			if err == nil {
+				return client, nil
+			}
+			_ = client.Close()
+		}
+	}
+
+	return m.dial(ctx)
+}
+
+// release returns the given client to the connection pool, if it's enabled and the connection is still usable, and
+// closes it otherwise.
+func (m *Mail) release(client *smtp.Client) {
+	if m.pool == nil {
+		_ = client.Quit()
+		return
+	}
+
+	if err := client.Reset(); err != nil {
+		_ = client.Close()
+		return
+	}
+
+	m.pool.put(client)
+}
+
+// sendMail sends the given message to all its receivers, including the BCC receivers.
+func (m *Mail) sendMail(ctx context.Context, msg *email.Email) error {
+	from, err := netmail.ParseAddress(msg.From)
+	if err != nil {
+		return fmt.Errorf("parse sender address: %w", err)
+	}
+
+	var receivers []string
+	for _, receiver := range slices.Concat(msg.To, msg.Cc, msg.Bcc) {
+		address, err := netmail.ParseAddress(receiver)
+		if err != nil {
+			return fmt.Errorf("parse receiver address: %w", err)
+		}
+		receivers = append(receivers, address.Address)
+	}
+
+	data, err := msg.Bytes()
+	if err != nil {
+		return fmt.Errorf("build message: %w", err)
+	}
+
+	client, err := m.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Abort any pending command once the context is done.
+	stop := context.AfterFunc(ctx, func() { _ = client.Close() })
+	err = transmit(client, from.Address, receivers, data)
+	if !stop() {
+		return errors.Join(err, ctx.Err())
+	}
+
+	m.release(client)
+
+	return err
+}
+
+// transmit runs a single mail transaction on the given client.
+func transmit(client *smtp.Client, from string, receivers []string, data []byte) error {
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("MAIL FROM: %w", err)
+	}
+
+	for _, receiver := range receivers {
+		if err := client.Rcpt(receiver); err != nil {
+			return fmt.Errorf("RCPT TO %s: %w", receiver, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("DATA: %w", err)
+	}
+
+	if _, err = writer.Write(data); err != nil {
+		_ = writer.Close()
+		return fmt.Errorf("write message: %w", err)
+	}
+
+	if err = writer.Close(); err != nil {
+		return fmt.Errorf("DATA: %w", err)
+	}
+
+	return nil
+}
+
+// connPool holds idle connections to the SMTP server.
+type connPool struct {
+	mu          sync.Mutex
+	idle        []idleClient
+	maxIdle     int
+	idleTimeout time.Duration
+	now         func() time.Time
+}
+
+// idleClient is a client in the connection pool.
+type idleClient struct {
+	client *smtp.Client
+	since  time.Time
+}
+
+// get returns the most recently used idle client, or nil if there is none. Clients that were idle for too long are
+// closed.
+func (p *connPool) get() *smtp.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for len(p.idle) > 0 {
+		idle := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+
+		if p.idleTimeout > 0 && p.now().Sub(idle.since) > p.idleTimeout {
+			_ = idle.client.Close()
+			continue
+		}
+
+		return idle.client
+	}
+
+	return nil
+}
+
+// put adds the given client to the pool, or closes it if the pool is full.
+func (p *connPool) put(client *smtp.Client) {
+	p.mu.Lock()
+	full := len(p.idle) >= p.maxIdle
+	if !full {
+		p.idle = append(p.idle, idleClient{client: client, since: p.now()})
+	}
+	p.mu.Unlock()
+
+	if full {
+		_ = client.Quit()
+	}
+}
+
+// close closes all idle clients.
+func (p *connPool) close() error {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle = nil
+	p.mu.Unlock()
+
+	var errs []error
+	for _, idle := range idle {
+		if err := idle.client.Quit(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
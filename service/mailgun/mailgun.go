@@ -3,8 +3,11 @@ package mailgun
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/mailgun/mailgun-go/v5"
+
+	"github.com/nikoksr/notify"
 )
 
 // Mailgun struct holds necessary data to communicate with the Mailgun API.
@@ -46,7 +49,11 @@ func (m Mailgun) Send(ctx context.Context, subject, message string) error {
 
 	_, err := m.client.Send(ctx, mailMessage)
 	if err != nil {
-		return fmt.Errorf("send message: %w", err)
+		return notify.NewReceiverError(
+			"mailgun",
+			strings.Join(m.receiverAddresses, ","),
+			fmt.Errorf("send message: %w", err),
+		)
 	}
 
 	return nil
@@ -3,10 +3,13 @@ package matrix
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	matrix "maunium.net/go/mautrix"
 	"maunium.net/go/mautrix/event"
 	"maunium.net/go/mautrix/id"
+
+	"github.com/nikoksr/notify"
 )
 
 type matrixClient interface {
@@ -56,12 +59,42 @@ func (s *Matrix) Send(ctx context.Context, _, message string) error {
 	default:
 		_, err := s.client.SendMessageEvent(ctx, s.options.roomID, event.EventMessage, &messageBody)
 		if err != nil {
-			return errors.New("failed to send message to the room using Matrix")
+			return newReceiverError(s.options.roomID, fmt.Errorf("send message to room %q: %w", s.options.roomID, err))
 		}
 	}
 	return nil
 }
 
+// newReceiverError wraps the given error into a notify.ReceiverError and classifies it based on the standard error
+// codes of the Matrix client-server API.
+func newReceiverError(roomID id.RoomID, err error) *notify.ReceiverError {
+	rErr := notify.NewReceiverError("matrix", roomID.String(), err)
+
+	var httpErr matrix.HTTPError
+	if !errors.As(err, &httpErr) {
+		return rErr
+	}
+
+	if httpErr.RespError != nil {
+		rErr.Code = httpErr.RespError.ErrCode
+	}
+	switch {
+	case errors.Is(err, matrix.MUnknownToken), errors.Is(err, matrix.MMissingToken):
+		rErr.SetKind(notify.ErrAuthFailure)
+	case errors.Is(err, matrix.MForbidden), errors.Is(err, matrix.MNotFound):
+		rErr.SetKind(notify.ErrInvalidReceiver)
+	case errors.Is(err, matrix.MLimitExceeded):
+		rErr.SetKind(notify.ErrRateLimited)
+	case errors.Is(err, matrix.MTooLarge):
+		rErr.SetKind(notify.ErrPayloadTooLarge)
+	}
+	if httpErr.Response != nil {
+		rErr.SetStatusCode(httpErr.Response.StatusCode)
+	}
+
+	return rErr
+}
+
 func createMessage(message string) Message {
 	return Message{
 		Body:    message,
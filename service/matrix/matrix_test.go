@@ -42,7 +42,7 @@ func TestMatrix_Send(t *testing.T) {
 				m.On("SendMessageEvent", mock.Anything, id.RoomID("!roomID:example.com"), event.EventMessage, mock.Anything).
 					Return(nil, errors.New("Matrix error"))
 			},
-			expectedError: "failed to send message to the room using Matrix",
+			expectedError: "send message to room \"!roomID:example.com\": Matrix error",
 		},
 	}
 
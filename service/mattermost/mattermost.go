@@ -7,6 +7,7 @@ import (
 	"io"
 	stdhttp "net/http"
 
+	"github.com/nikoksr/notify"
 	"github.com/nikoksr/notify/service/http"
 )
 
@@ -58,7 +59,7 @@ func (s *Service) Send(ctx context.Context, subject, message string) error {
 		default:
 			// create post
 			if err := s.messageClient.Send(ctx, id, subject+"\n"+message); err != nil {
-				return fmt.Errorf("send message to channel %q: %w", id, err)
+				return notify.NewReceiverError("mattermost", id, fmt.Errorf("send message to channel %q: %w", id, err))
 			}
 		}
 	}
@@ -76,6 +77,22 @@ func (s *Service) PostSend(hook http.PostSendHookFn) {
 	s.messageClient.PostSend(hook)
 }
 
+// responseError is returned when the Mattermost API responded with an unexpected status code.
+type responseError struct {
+	op   string
+	resp *stdhttp.Response
+	body []byte
+}
+
+func (e *responseError) Error() string {
+	return fmt.Sprintf("%s failed with status: %s body: %s", e.op, e.resp.Status, string(e.body))
+}
+
+// StatusCode returns the HTTP status code of the response. It's picked up by notify.NewReceiverError.
+func (e *responseError) StatusCode() int {
+	return e.resp.StatusCode
+}
+
 // setups main message service for creating posts.
 func setupMsgService(url string) *http.Service {
 	// create new http client for sending messages/notifications
@@ -99,7 +116,7 @@ func setupMsgService(url string) *http.Service {
 	httpService.PostSend(func(_ *stdhttp.Request, resp *stdhttp.Response) error {
 		if resp.StatusCode != stdhttp.StatusCreated {
 			b, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("create post failed with status: %s body: %s", resp.Status, string(b))
+			return &responseError{op: "create post", resp: resp, body: b}
 		}
 		return nil
 	})
@@ -132,7 +149,7 @@ func setupLoginService(url string, msgService *http.Service) *http.Service {
 	httpService.PostSend(func(_ *stdhttp.Request, resp *stdhttp.Response) error {
 		if resp.StatusCode != stdhttp.StatusOK {
 			b, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("login failed with status: %s body: %s", resp.Status, string(b))
+			return &responseError{op: "login", resp: resp, body: b}
 		}
 
 		// get token from header
@@ -7,6 +7,8 @@ import (
 
 	teams "github.com/atc0005/go-teams-notify/v2"
 	"github.com/atc0005/go-teams-notify/v2/adaptivecard"
+
+	"github.com/nikoksr/notify"
 )
 
 type teamsClient interface {
@@ -93,7 +95,7 @@ func (m MSTeams) Send(ctx context.Context, subject, message string) error {
 			return ctx.Err()
 		default:
 			if err = m.client.SendWithContext(ctx, webHook, msg); err != nil {
-				return fmt.Errorf("send message to channel %q: %w", webHook, err)
+				return notify.NewReceiverError("msteams", webHook, fmt.Errorf("send message to channel %q: %w", webHook, err))
 			}
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/PagerDuty/go-pagerduty"
 
@@ -18,9 +19,18 @@ type Client interface {
 	) (*pagerduty.Incident, error)
 }
 
+// abilitiesLister is implemented by clients that can list the abilities of the account, which is used to validate the
+// access token. The official PagerDuty client implements it.
+type abilitiesLister interface {
+	ListAbilitiesWithContext(ctx context.Context) (*pagerduty.ListAbilityResponse, error)
+}
+
 // Compile-time check to verify that the PagerDuty type implements the notifier.Notifier interface.
 var _ notify.Notifier = &PagerDuty{}
 
+// Compile-time check to verify that the PagerDuty type implements the notifier.Checker interface.
+var _ notify.Checker = &PagerDuty{}
+
 type PagerDuty struct {
 	*Config
 
@@ -40,12 +50,18 @@ func New(token string, clientOptions ...pagerduty.ClientOptions) (*PagerDuty, er
 	return pagerDuty, nil
 }
 
+// Send creates an incident with the given subject and message for every receiver. The notify.Priority carried by the
+// context, if any, overrides the configured urgency: notify.PriorityHigh and above result in high urgency, everything
+// else in low urgency.
 func (s *PagerDuty) Send(ctx context.Context, subject, message string) error {
 	if err := s.Config.OK(); err != nil {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
 
 	incident := s.IncidentOptions(subject, message)
+	if priority, ok := notify.PriorityFromContext(ctx); ok {
+		incident.Urgency = urgencyOf(priority)
+	}
 
 	for _, receiver := range s.Config.Receivers {
 		// set the service ID to the receiver
@@ -53,7 +69,7 @@ func (s *PagerDuty) Send(ctx context.Context, subject, message string) error {
 
 		_, err := s.Client.CreateIncidentWithContext(ctx, s.Config.FromAddress, incident)
 		if err != nil {
-			return fmt.Errorf("create pager duty incident: %w", err)
+			return newReceiverError(receiver, fmt.Errorf("create pager duty incident: %w", err))
 		}
 	}
 
@@ -75,3 +91,46 @@ func (s *PagerDuty) IncidentOptions(subject, message string) *pagerduty.CreateIn
 		Urgency:  s.Config.Urgency,
 	}
 }
+
+// urgencyOf translates the given notify.Priority into a PagerDuty incident urgency.
+func urgencyOf(priority notify.Priority) string {
+	if priority >= notify.PriorityHigh {
+		return "high"
+	}
+
+	return "low"
+}
+
+// Check validates the access token by listing the abilities of the PagerDuty account. It implements the
+// notify.Checker interface. Custom clients that don't implement ListAbilitiesWithContext can't be checked and result
+// in an error.
+func (s *PagerDuty) Check(ctx context.Context) error {
+	client, ok := s.Client.(abilitiesLister)
+	if !ok {
+		return fmt.Errorf("client of type %T does not support token validation", s.Client)
+	}
+
+	if _, err := client.ListAbilitiesWithContext(ctx); err != nil {
+		return newReceiverError("", fmt.Errorf("list abilities: %w", err))
+	}
+
+	return nil
+}
+
+// newReceiverError wraps the given error into a notify.ReceiverError and classifies it based on the HTTP status code
+// returned by the PagerDuty API.
+func newReceiverError(serviceID string, err error) *notify.ReceiverError {
+	rErr := notify.NewReceiverError("pagerduty", serviceID, err)
+
+	var apiErr pagerduty.APIError
+	if !errors.As(err, &apiErr) {
+		return rErr
+	}
+
+	if apiErr.APIError.Valid {
+		rErr.Code = strconv.Itoa(apiErr.APIError.ErrorObject.Code)
+	}
+	rErr.SetStatusCode(apiErr.StatusCode)
+
+	return rErr
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/nikoksr/notify"
 	"github.com/nikoksr/notify/service/pagerduty"
 )
 
@@ -150,3 +151,59 @@ func TestPagerDuty_Send(t *testing.T) {
 		})
 	}
 }
+
+func TestPagerDuty_Send_Priority(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		wantUrgency string
+	}{
+		{
+			name:        "configured_urgency_without_priority",
+			ctx:         context.Background(),
+			wantUrgency: "low",
+		},
+		{
+			name:        "urgent_priority",
+			ctx:         notify.WithPriority(context.Background(), notify.PriorityUrgent),
+			wantUrgency: "high",
+		},
+		{
+			name:        "critical_severity",
+			ctx:         notify.WithSeverity(context.Background(), notify.SeverityCritical),
+			wantUrgency: "high",
+		},
+		{
+			name:        "default_priority",
+			ctx:         notify.WithPriority(context.Background(), notify.PriorityDefault),
+			wantUrgency: "low",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockClient := new(mockClient)
+			mockClient.
+				On("CreateIncidentWithContext", mock.Anything, "sender@domain.com",
+					mock.MatchedBy(func(options *gopagerduty.CreateIncidentOptions) bool {
+						return options.Urgency == test.wantUrgency
+					})).
+				Return(&gopagerduty.Incident{}, nil)
+
+			service, err := pagerduty.New("fake_token")
+			require.NoError(t, err)
+
+			service.AddReceivers("AB1234")
+			service.SetFromAddress("sender@domain.com")
+			service.SetUrgency("low")
+			service.Client = mockClient
+
+			require.NoError(t, service.Send(test.ctx, "Test Subject", "Test Message"))
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	plivo "github.com/plivo/plivo-go/v7"
+
+	"github.com/nikoksr/notify"
 )
 
 // ClientOptions allow you to configure a Plivo SDK client.
@@ -104,7 +106,7 @@ func (s *Service) Send(ctx context.Context, subject, message string) error {
 			Method: s.mopts.CallbackMethod,
 		})
 		if err != nil {
-			return fmt.Errorf("send SMS to %q: %w", dst, err)
+			return notify.NewReceiverError("plivo", dst, fmt.Errorf("send SMS to %q: %w", dst, err))
 		}
 	}
 
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/cschomburg/go-pushbullet"
+
+	"github.com/nikoksr/notify"
 )
 
 // Pushbullet struct holds necessary data to communicate with the Pushbullet API.
@@ -46,11 +48,19 @@ func (pb Pushbullet) Send(ctx context.Context, subject, message string) error {
 		default:
 			dev, err := pb.client.Device(deviceNickname)
 			if err != nil {
-				return fmt.Errorf("get device with nickname %q: %w", deviceNickname, err)
+				return notify.NewReceiverError(
+					"pushbullet",
+					deviceNickname,
+					fmt.Errorf("get device with nickname %q: %w", deviceNickname, err),
+				).SetKind(notify.ErrInvalidReceiver)
 			}
 
 			if err = dev.PushNote(subject, message); err != nil {
-				return fmt.Errorf("send push to %q: %w", deviceNickname, err)
+				return notify.NewReceiverError(
+					"pushbullet",
+					deviceNickname,
+					fmt.Errorf("send push to %q: %w", deviceNickname, err),
+				)
 			}
 		}
 	}
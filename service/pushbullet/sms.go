@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/cschomburg/go-pushbullet"
+
+	"github.com/nikoksr/notify"
 )
 
 // SMS struct holds necessary data to communicate with the Pushbullet SMS API.
@@ -59,7 +61,7 @@ func (sms SMS) Send(ctx context.Context, subject, message string) error {
 			return ctx.Err()
 		default:
 			if err = sms.client.PushSMS(user.Iden, sms.deviceIdentifier, phoneNumber, fullMessage); err != nil {
-				return fmt.Errorf("send SMS to %q: %w", phoneNumber, err)
+				return notify.NewReceiverError("pushbullet", phoneNumber, fmt.Errorf("send SMS to %q: %w", phoneNumber, err))
 			}
 		}
 	}
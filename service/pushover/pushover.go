@@ -1,10 +1,16 @@
 package pushover
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/gregdel/pushover"
+
+	"github.com/nikoksr/notify"
 )
 
 type pushoverClient interface {
@@ -14,10 +20,20 @@ type pushoverClient interface {
 // Compile-time check to ensure that pushover.Pushover implements the pushoverClient interface.
 var _ pushoverClient = new(pushover.Pushover)
 
+// These are the defaults for how often and how long Pushover retries messages with emergency priority until they get
+// acknowledged.
+const (
+	DefaultEmergencyRetry  = time.Minute
+	DefaultEmergencyExpire = time.Hour
+)
+
 // Pushover struct holds necessary data to communicate with the Pushover API.
 type Pushover struct {
-	client     pushoverClient
-	recipients []pushover.Recipient
+	client          pushoverClient
+	recipients      []string
+	delivery        notify.DeliveryOptions
+	emergencyRetry  time.Duration
+	emergencyExpire time.Duration
 }
 
 // New returns a new instance of a Pushover notification service.
@@ -29,7 +45,7 @@ func New(appToken string) *Pushover {
 
 	s := &Pushover{
 		client:     client,
-		recipients: []pushover.Recipient{},
+		recipients: []string{},
 	}
 
 	return s
@@ -38,26 +54,95 @@ func New(appToken string) *Pushover {
 // AddReceivers takes Pushover user/group IDs and adds them to the internal recipient list. The Send method will send
 // a given message to all of those recipients.
 func (p *Pushover) AddReceivers(recipientIDs ...string) {
-	for _, recipient := range recipientIDs {
-		p.recipients = append(p.recipients, *pushover.NewRecipient(recipient))
+	p.recipients = append(p.recipients, recipientIDs...)
+}
+
+// SetDeliveryOptions configures how the Send method delivers a message to multiple recipients. By default, recipients are
+// processed sequentially and delivery stops at the first error.
+func (p *Pushover) SetDeliveryOptions(opts notify.DeliveryOptions) {
+	p.delivery = opts
+}
+
+// SetEmergencyParameters sets how often and for how long Pushover retries a message with emergency priority until it
+// gets acknowledged. Messages get emergency priority if they are sent with notify.PriorityUrgent. Zero values are
+// replaced by DefaultEmergencyRetry and DefaultEmergencyExpire.
+func (p *Pushover) SetEmergencyParameters(retry, expire time.Duration) {
+	p.emergencyRetry = retry
+	p.emergencyExpire = expire
+}
+
+// newMessage creates a new Pushover message and sets its priority according to the notify.Priority carried by the
+// given context, if any.
+func (p Pushover) newMessage(ctx context.Context, subject, message string) *pushover.Message {
+	msg := pushover.NewMessageWithTitle(message, subject)
+
+	priority, ok := notify.PriorityFromContext(ctx)
+	if !ok {
+		return msg
+	}
+
+	switch {
+	case priority <= notify.PriorityMin:
+		msg.Priority = pushover.PriorityLowest
+	case priority == notify.PriorityLow:
+		msg.Priority = pushover.PriorityLow
+	case priority == notify.PriorityDefault:
+		msg.Priority = pushover.PriorityNormal
+	case priority == notify.PriorityHigh:
+		msg.Priority = pushover.PriorityHigh
+	default:
+		msg.Priority = pushover.PriorityEmergency
+		msg.Retry = cmp.Or(p.emergencyRetry, DefaultEmergencyRetry)
+		msg.Expire = cmp.Or(p.emergencyExpire, DefaultEmergencyExpire)
 	}
+
+	return msg
 }
 
-// Send takes a message subject and a message body and sends them to all previously set recipients.
+// Send takes a message subject and a message body and sends them to all previously set recipients. The notify.Priority
+// carried by the context, if any, is translated into the Pushover message priority.
 func (p Pushover) Send(ctx context.Context, subject, message string) error {
-	for i := range p.recipients {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			_, err := p.client.SendMessage(
-				pushover.NewMessageWithTitle(message, subject),
-				&p.recipients[i],
-			)
-			if err != nil {
-				return fmt.Errorf("send message to recipient %d: %w", i+1, err)
-			}
+	msg := p.newMessage(ctx, subject, message)
+
+	return notify.Deliver(ctx, p.delivery, p.recipients, func(_ context.Context, recipient string) error {
+		_, err := p.client.SendMessage(msg, pushover.NewRecipient(recipient))
+		if err != nil {
+			return newReceiverError(recipient, fmt.Errorf("send message to recipient %s: %w", recipient, err))
 		}
+
+		return nil
+	})
+}
+
+// newReceiverError wraps the given error into a notify.ReceiverError and classifies it based on the errors returned by
+// the Pushover client. The receiver is identified by its user or group key.
+func newReceiverError(recipient string, err error) *notify.ReceiverError {
+	rErr := notify.NewReceiverError("pushover", recipient, err)
+
+	switch {
+	case errors.Is(err, pushover.ErrHTTPPushover):
+		rErr.Retryable = true
+	case errors.Is(err, pushover.ErrInvalidToken), errors.Is(err, pushover.ErrEmptyToken):
+		rErr.SetKind(notify.ErrAuthFailure)
+	case errors.Is(err, pushover.ErrInvalidRecipientToken), errors.Is(err, pushover.ErrEmptyRecipientToken):
+		rErr.SetKind(notify.ErrInvalidReceiver)
+	case errors.Is(err, pushover.ErrMessageTooLong), errors.Is(err, pushover.ErrMessageTitleTooLong):
+		rErr.SetKind(notify.ErrPayloadTooLarge)
 	}
-	return nil
+
+	// The API reports errors as plain text messages.
+	var apiErrs pushover.Errors
+	if errors.As(err, &apiErrs) {
+		msg := strings.ToLower(apiErrs.Error())
+		switch {
+		case strings.Contains(msg, "application token is invalid"):
+			rErr.SetKind(notify.ErrAuthFailure)
+		case strings.Contains(msg, "user identifier"), strings.Contains(msg, "user key"):
+			rErr.SetKind(notify.ErrInvalidReceiver)
+		case strings.Contains(msg, "cannot be longer"):
+			rErr.SetKind(notify.ErrPayloadTooLarge)
+		}
+	}
+
+	return rErr
 }
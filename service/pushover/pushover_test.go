@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/gregdel/pushover"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/notify"
 )
 
 func TestPushover_Send(t *testing.T) {
@@ -15,7 +18,7 @@ func TestPushover_Send(t *testing.T) {
 
 	tests := []struct {
 		name          string
-		recipients    []pushover.Recipient
+		recipients    []string
 		subject       string
 		message       string
 		mockSetup     func(*mockpushoverClient)
@@ -23,7 +26,7 @@ func TestPushover_Send(t *testing.T) {
 	}{
 		{
 			name:       "Successful send to single recipient",
-			recipients: []pushover.Recipient{*pushover.NewRecipient("recipient1")},
+			recipients: []string{"recipient1"},
 			subject:    "Test Subject",
 			message:    "Test Message",
 			mockSetup: func(m *mockpushoverClient) {
@@ -33,13 +36,10 @@ func TestPushover_Send(t *testing.T) {
 			expectedError: "",
 		},
 		{
-			name: "Successful send to multiple recipients",
-			recipients: []pushover.Recipient{
-				*pushover.NewRecipient("recipient1"),
-				*pushover.NewRecipient("recipient2"),
-			},
-			subject: "Test Subject",
-			message: "Test Message",
+			name:       "Successful send to multiple recipients",
+			recipients: []string{"recipient1", "recipient2"},
+			subject:    "Test Subject",
+			message:    "Test Message",
 			mockSetup: func(m *mockpushoverClient) {
 				m.On("SendMessage", mock.AnythingOfType("*pushover.Message"), mock.AnythingOfType("*pushover.Recipient")).
 					Return(&pushover.Response{}, nil).
@@ -49,14 +49,14 @@ func TestPushover_Send(t *testing.T) {
 		},
 		{
 			name:       "Pushover client error",
-			recipients: []pushover.Recipient{*pushover.NewRecipient("recipient1")},
+			recipients: []string{"recipient1"},
 			subject:    "Test Subject",
 			message:    "Test Message",
 			mockSetup: func(m *mockpushoverClient) {
 				m.On("SendMessage", mock.AnythingOfType("*pushover.Message"), mock.AnythingOfType("*pushover.Recipient")).
 					Return(nil, errors.New("Pushover error"))
 			},
-			expectedError: "send message to recipient 1: Pushover error",
+			expectedError: "send message to recipient recipient1: Pushover error",
 		},
 	}
 
@@ -76,6 +76,10 @@ func TestPushover_Send(t *testing.T) {
 
 			if tt.expectedError != "" {
 				require.EqualError(t, err, tt.expectedError)
+
+				var rErr *notify.ReceiverError
+				require.ErrorAs(t, err, &rErr)
+				require.Equal(t, tt.recipients[0], rErr.Receiver)
 			} else {
 				require.NoError(t, err)
 			}
@@ -84,3 +88,57 @@ func TestPushover_Send(t *testing.T) {
 		})
 	}
 }
+
+func TestPushover_Send_Priority(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		wantPriority int
+		wantRetry    time.Duration
+	}{
+		{
+			name:         "No priority",
+			ctx:          context.Background(),
+			wantPriority: pushover.PriorityNormal,
+		},
+		{
+			name:         "Explicit low priority",
+			ctx:          notify.WithPriority(context.Background(), notify.PriorityLow),
+			wantPriority: pushover.PriorityLow,
+		},
+		{
+			name:         "Priority derived from severity",
+			ctx:          notify.WithSeverity(context.Background(), notify.SeverityError),
+			wantPriority: pushover.PriorityHigh,
+		},
+		{
+			name:         "Urgent priority becomes emergency",
+			ctx:          notify.WithPriority(context.Background(), notify.PriorityUrgent),
+			wantPriority: pushover.PriorityEmergency,
+			wantRetry:    DefaultEmergencyRetry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockClient := new(mockpushoverClient)
+			mockClient.
+				On("SendMessage", mock.MatchedBy(func(msg *pushover.Message) bool {
+					return msg.Priority == tt.wantPriority && msg.Retry == tt.wantRetry
+				}), mock.AnythingOfType("*pushover.Recipient")).
+				Return(&pushover.Response{}, nil)
+
+			p := &Pushover{
+				client:     mockClient,
+				recipients: []string{"recipient1"},
+			}
+
+			require.NoError(t, p.Send(tt.ctx, "Test Subject", "Test Message"))
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
@@ -3,10 +3,13 @@ package reddit
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/caarlos0/go-reddit/v3/reddit"
+
+	"github.com/nikoksr/notify"
 )
 
 type redditMessageClient interface {
@@ -20,6 +23,7 @@ var _ redditMessageClient = new(reddit.MessageService)
 type Reddit struct {
 	client     redditMessageClient
 	recipients []string
+	delivery   notify.DeliveryOptions
 }
 
 // New returns a new instance of a Reddit notification service.
@@ -63,24 +67,43 @@ func (r *Reddit) AddReceivers(recipients ...string) {
 	r.recipients = append(r.recipients, recipients...)
 }
 
+// SetDeliveryOptions configures how the Send method delivers a message to multiple recipients. By default, recipients are
+// processed sequentially and delivery stops at the first error.
+func (r *Reddit) SetDeliveryOptions(opts notify.DeliveryOptions) {
+	r.delivery = opts
+}
+
 // Send takes a message subject and a message body and sends them to all previously set recipients.
 func (r *Reddit) Send(ctx context.Context, subject, message string) error {
-	for i := range r.recipients {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			m := reddit.SendMessageRequest{
-				To:      r.recipients[i],
-				Subject: subject,
-				Text:    message,
-			}
-
-			if _, err := r.client.Send(ctx, &m); err != nil {
-				return fmt.Errorf("send message to user %q: %w", r.recipients[i], err)
-			}
+	return notify.Deliver(ctx, r.delivery, r.recipients, func(ctx context.Context, recipient string) error {
+		m := reddit.SendMessageRequest{
+			To:      recipient,
+			Subject: subject,
+			Text:    message,
 		}
+
+		if _, err := r.client.Send(ctx, &m); err != nil {
+			return newReceiverError(recipient, fmt.Errorf("send message to user %q: %w", recipient, err))
+		}
+
+		return nil
+	})
+}
+
+// newReceiverError wraps the given error into a notify.ReceiverError and classifies it based on the errors returned by
+// the Reddit client.
+func newReceiverError(recipient string, err error) *notify.ReceiverError {
+	rErr := notify.NewReceiverError("reddit", recipient, err)
+
+	var rateLimitErr *reddit.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return rErr.SetKind(notify.ErrRateLimited)
+	}
+
+	var apiErr *reddit.ErrorResponse
+	if errors.As(err, &apiErr) && apiErr.Response != nil {
+		rErr.SetStatusCode(apiErr.Response.StatusCode)
 	}
 
-	return nil
+	return rErr
 }
@@ -7,6 +7,8 @@ import (
 
 	"github.com/RocketChat/Rocket.Chat.Go.SDK/models"
 	"github.com/RocketChat/Rocket.Chat.Go.SDK/rest"
+
+	"github.com/nikoksr/notify"
 )
 
 // RocketChat struct holds necessary data to communicate with the RocketChat API.
@@ -64,7 +66,11 @@ func (r *RocketChat) Send(ctx context.Context, subject, message string) error {
 			}
 			_, err := r.client.PostMessage(&msg)
 			if err != nil {
-				return fmt.Errorf("send message to channel %q: %w", channelName, err)
+				return notify.NewReceiverError(
+					"rocketchat",
+					channelName,
+					fmt.Errorf("send message to channel %q: %w", channelName, err),
+				)
 			}
 		}
 	}
@@ -5,14 +5,19 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/sendgrid/sendgrid-go"
 	"github.com/sendgrid/sendgrid-go/helpers/mail"
+
+	"github.com/nikoksr/notify"
+	"github.com/nikoksr/notify/internal/htmltext"
 )
 
 // SendGrid struct holds necessary data to communicate with the SendGrid API.
 type SendGrid struct {
 	usePlainText      bool
+	generatePlainText bool
 	client            *sendgrid.Client
 	senderAddress     string
 	senderName        string
@@ -27,6 +32,9 @@ const (
 	PlainText BodyType = iota
 	// HTML is used to specify that the body is HTML.
 	HTML
+	// Alternative is used to specify that the body is HTML, which is sent together with a plain-text version that is
+	// generated from it.
+	Alternative
 )
 
 // New returns a new instance of a SendGrid notification service.
@@ -54,24 +62,44 @@ func (s *SendGrid) BodyFormat(format BodyType) {
 	switch format {
 	case PlainText:
 		s.usePlainText = true
+		s.generatePlainText = false
 	case HTML:
 		s.usePlainText = false
+		s.generatePlainText = false
+	case Alternative:
+		s.usePlainText = false
+		s.generatePlainText = true
 	default:
 		s.usePlainText = false
+		s.generatePlainText = false
+	}
+}
+
+// contents returns the contents of a mail with the given message. HTML messages get a plain-text alternative, either
+// from the given context or generated from the HTML if the body format is Alternative. SendGrid requires the plain
+// text to come first.
+func (s *SendGrid) contents(ctx context.Context, message string) []*mail.Content {
+	if s.usePlainText {
+		return []*mail.Content{mail.NewContent("text/plain", message)}
+	}
+
+	html := mail.NewContent("text/html", message)
+	if text, ok := notify.PlainTextFromContext(ctx); ok {
+		return []*mail.Content{mail.NewContent("text/plain", text), html}
+	}
+	if s.generatePlainText {
+		return []*mail.Content{mail.NewContent("text/plain", htmltext.Convert(message)), html}
 	}
+
+	return []*mail.Content{html}
 }
 
 // Send takes a message subject and a message body and sends them to all previously set chats. Message body supports
-// html as markup language.
+// html as markup language. HTML messages are sent as multipart/alternative together with the plain text carried by the
+// context, see notify.WithPlainText. Without it, the plain text is generated from the HTML if the body format is
+// Alternative.
 func (s SendGrid) Send(ctx context.Context, subject, message string) error {
 	from := mail.NewEmail(s.senderName, s.senderAddress)
-	var contentType string
-	if s.usePlainText {
-		contentType = "text/plain"
-	} else {
-		contentType = "text/html"
-	}
-	content := mail.NewContent(contentType, message)
 
 	// Create a new personalization instance to be able to add multiple receiver addresses.
 	personalization := mail.NewPersonalization()
@@ -83,16 +111,24 @@ func (s SendGrid) Send(ctx context.Context, subject, message string) error {
 
 	mailMessage := mail.NewV3Mail()
 	mailMessage.AddPersonalizations(personalization)
-	mailMessage.AddContent(content)
+	mailMessage.AddContent(s.contents(ctx, message)...)
 	mailMessage.SetFrom(from)
 
+	receivers := strings.Join(s.receiverAddresses, ",")
+
 	resp, err := s.client.SendWithContext(ctx, mailMessage)
 	if err != nil {
-		return fmt.Errorf("send message: %w", err)
+		return notify.NewReceiverError("sendgrid", receivers, fmt.Errorf("send message: %w", err))
 	}
 
 	if resp.StatusCode != http.StatusAccepted {
-		return errors.New("the SendGrid endpoint did not accept the message")
+		rErr := notify.NewReceiverError(
+			"sendgrid",
+			receivers,
+			errors.New("the SendGrid endpoint did not accept the message"),
+		)
+
+		return rErr.SetStatusCode(resp.StatusCode)
 	}
 
 	return nil
@@ -38,6 +38,68 @@ func (_m *mockslackClient) EXPECT() *mockslackClient_Expecter {
 	return &mockslackClient_Expecter{mock: &_m.Mock}
 }
 
+// AuthTestContext provides a mock function for the type mockslackClient
+func (_mock *mockslackClient) AuthTestContext(ctx context.Context) (*slack.AuthTestResponse, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthTestContext")
+	}
+
+	var r0 *slack.AuthTestResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (*slack.AuthTestResponse, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) *slack.AuthTestResponse); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*slack.AuthTestResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// mockslackClient_AuthTestContext_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AuthTestContext'
+type mockslackClient_AuthTestContext_Call struct {
+	*mock.Call
+}
+
+// AuthTestContext is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockslackClient_Expecter) AuthTestContext(ctx interface{}) *mockslackClient_AuthTestContext_Call {
+	return &mockslackClient_AuthTestContext_Call{Call: _e.mock.On("AuthTestContext", ctx)}
+}
+
+func (_c *mockslackClient_AuthTestContext_Call) Run(run func(ctx context.Context)) *mockslackClient_AuthTestContext_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *mockslackClient_AuthTestContext_Call) Return(authTestResponse *slack.AuthTestResponse, err error) *mockslackClient_AuthTestContext_Call {
+	_c.Call.Return(authTestResponse, err)
+	return _c
+}
+
+func (_c *mockslackClient_AuthTestContext_Call) RunAndReturn(run func(ctx context.Context) (*slack.AuthTestResponse, error)) *mockslackClient_AuthTestContext_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // PostMessageContext provides a mock function for the type mockslackClient
 func (_mock *mockslackClient) PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error) {
 	// slack.MsgOption
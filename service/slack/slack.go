@@ -2,12 +2,16 @@ package slack
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/slack-go/slack"
+
+	"github.com/nikoksr/notify"
 )
 
 type slackClient interface {
+	AuthTestContext(ctx context.Context) (*slack.AuthTestResponse, error)
 	PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error)
 }
 
@@ -18,6 +22,7 @@ var _ slackClient = new(slack.Client)
 type Slack struct {
 	client     slackClient
 	channelIDs []string
+	delivery   notify.DeliveryOptions
 }
 
 // New returns a new instance of a Slack notification service.
@@ -41,27 +46,75 @@ func (s *Slack) AddReceivers(channelIDs ...string) {
 	s.channelIDs = append(s.channelIDs, channelIDs...)
 }
 
+// SetDeliveryOptions configures how the Send method delivers a message to multiple channels. By default, channels are
+// processed sequentially and delivery stops at the first error.
+func (s *Slack) SetDeliveryOptions(opts notify.DeliveryOptions) {
+	s.delivery = opts
+}
+
 // Send takes a message subject and a message body and sends them to all previously set channels.
 // you will need a slack app with the chat:write.public and chat:write permissions.
 // see https://api.slack.com/
 func (s Slack) Send(ctx context.Context, subject, message string) error {
 	fullMessage := subject + "\n" + message // Treating subject as message title
 
-	for _, channelID := range s.channelIDs {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			_, _, err := s.client.PostMessageContext(
-				ctx,
-				channelID,
-				slack.MsgOptionText(fullMessage, false),
-			)
-			if err != nil {
-				return fmt.Errorf("send message to channel %q: %w", channelID, err)
-			}
+	return notify.Deliver(ctx, s.delivery, s.channelIDs, func(ctx context.Context, channelID string) error {
+		_, _, err := s.client.PostMessageContext(
+			ctx,
+			channelID,
+			slack.MsgOptionText(fullMessage, false),
+		)
+		if err != nil {
+			return newReceiverError(channelID, fmt.Errorf("send message to channel %q: %w", channelID, err))
 		}
+
+		return nil
+	})
+}
+
+// Check verifies the API token by calling the auth.test endpoint of the Slack API. It implements the notify.Checker
+// interface.
+func (s Slack) Check(ctx context.Context) error {
+	if _, err := s.client.AuthTestContext(ctx); err != nil {
+		return newReceiverError("", fmt.Errorf("test authentication: %w", err))
 	}
 
 	return nil
 }
+
+// newReceiverError wraps the given error into a notify.ReceiverError and classifies it based on the error codes
+// returned by the Slack API. See https://api.slack.com/methods/chat.postMessage#errors.
+func newReceiverError(channelID string, err error) *notify.ReceiverError {
+	rErr := notify.NewReceiverError("slack", channelID, err)
+
+	var rateLimitErr *slack.RateLimitedError
+	if errors.As(err, &rateLimitErr) {
+		return rErr.SetKind(notify.ErrRateLimited)
+	}
+
+	var statusErr slack.StatusCodeError
+	if errors.As(err, &statusErr) {
+		return rErr.SetStatusCode(statusErr.Code)
+	}
+
+	var apiErr slack.SlackErrorResponse
+	if !errors.As(err, &apiErr) {
+		return rErr
+	}
+
+	rErr.Code = apiErr.Err
+	switch apiErr.Err {
+	case "invalid_auth", "not_authed", "account_inactive", "token_revoked", "token_expired", "missing_scope":
+		rErr.SetKind(notify.ErrAuthFailure)
+	case "channel_not_found", "is_archived", "not_in_channel", "user_not_found", "restricted_action":
+		rErr.SetKind(notify.ErrInvalidReceiver)
+	case "msg_too_long", "msg_blocks_too_long", "too_many_attachments":
+		rErr.SetKind(notify.ErrPayloadTooLarge)
+	case "ratelimited", "rate_limited":
+		rErr.SetKind(notify.ErrRateLimited)
+	case "internal_error", "service_unavailable", "fatal_error", "request_timeout":
+		rErr.Retryable = true
+	}
+
+	return rErr
+}
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/slack-go/slack"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/notify"
 )
 
 func TestSlack_Send(t *testing.T) {
@@ -80,3 +83,65 @@ func TestSlack_Send(t *testing.T) {
 		})
 	}
 }
+
+func TestSlack_Send_ContinueOnError(t *testing.T) {
+	t.Parallel()
+
+	mockClient := new(mockslackClient)
+	mockClient.On("PostMessageContext", mock.Anything, "C1", mock.AnythingOfType("slack.MsgOption")).
+		Return("", "", errors.New("channel_not_found"))
+	mockClient.On("PostMessageContext", mock.Anything, "C2", mock.AnythingOfType("slack.MsgOption")).
+		Return("", "", nil)
+
+	s := &Slack{
+		client:     mockClient,
+		channelIDs: []string{"C1", "C2"},
+	}
+	s.SetDeliveryOptions(notify.DeliveryOptions{ContinueOnError: true})
+
+	err := s.Send(context.Background(), "Test Subject", "Test Message")
+
+	require.EqualError(t, err, "send message to channel \"C1\": channel_not_found")
+	mockClient.AssertExpectations(t)
+}
+
+func TestSlack_Send_ReceiverError(t *testing.T) {
+	t.Parallel()
+
+	mockClient := new(mockslackClient)
+	mockClient.On("PostMessageContext", mock.Anything, "C1", mock.AnythingOfType("slack.MsgOption")).
+		Return("", "", slack.SlackErrorResponse{Err: "channel_not_found"})
+
+	s := &Slack{
+		client:     mockClient,
+		channelIDs: []string{"C1"},
+	}
+
+	err := s.Send(context.Background(), "Test Subject", "Test Message")
+
+	var rErr *notify.ReceiverError
+	require.ErrorAs(t, err, &rErr)
+	require.Equal(t, "slack", rErr.Service)
+	require.Equal(t, "C1", rErr.Receiver)
+	require.Equal(t, "channel_not_found", rErr.Code)
+	require.ErrorIs(t, err, notify.ErrInvalidReceiver)
+	mockClient.AssertExpectations(t)
+}
+
+func TestSlack_Check(t *testing.T) {
+	t.Parallel()
+
+	mockClient := new(mockslackClient)
+	mockClient.On("AuthTestContext", mock.Anything).
+		Return(nil, slack.SlackErrorResponse{Err: "invalid_auth"}).Once()
+	mockClient.On("AuthTestContext", mock.Anything).
+		Return(&slack.AuthTestResponse{}, nil).Once()
+
+	s := &Slack{client: mockClient}
+
+	err := s.Check(context.Background())
+	require.ErrorIs(t, err, notify.ErrAuthFailure)
+
+	require.NoError(t, s.Check(context.Background()))
+	mockClient.AssertExpectations(t)
+}
@@ -2,9 +2,14 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/nikoksr/notify"
 )
 
 const (
@@ -19,8 +24,9 @@ var parseMode = ModeHTML
 
 // Telegram struct holds necessary data to communicate with the Telegram API.
 type Telegram struct {
-	client  *tgbotapi.BotAPI
-	chatIDs []int64
+	client   *tgbotapi.BotAPI
+	chatIDs  []int64
+	delivery notify.DeliveryOptions
 }
 
 // New returns a new instance of a Telegram notification service.
@@ -63,25 +69,67 @@ func (t *Telegram) AddReceivers(chatIDs ...int64) {
 	t.chatIDs = append(t.chatIDs, chatIDs...)
 }
 
+// SetDeliveryOptions configures how the Send method delivers a message to multiple chats. By default, chats are
+// processed sequentially and delivery stops at the first error.
+func (t *Telegram) SetDeliveryOptions(opts notify.DeliveryOptions) {
+	t.delivery = opts
+}
+
 // Send takes a message subject and a message body and sends them to all previously set chats. Message body supports
 // html as markup language.
 func (t Telegram) Send(ctx context.Context, subject, message string) error {
 	fullMessage := subject + "\n" + message // Treating subject as message title
 
-	msg := tgbotapi.NewMessage(0, fullMessage)
-	msg.ParseMode = parseMode
-
-	for _, chatID := range t.chatIDs {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			msg.ChatID = chatID
-			if _, err := t.client.Send(msg); err != nil {
-				return fmt.Errorf("send message to chat %d: %w", chatID, err)
-			}
+	return notify.Deliver(ctx, t.delivery, t.chatIDs, func(_ context.Context, chatID int64) error {
+		msg := tgbotapi.NewMessage(chatID, fullMessage)
+		msg.ParseMode = parseMode
+
+		if _, err := t.client.Send(msg); err != nil {
+			return newReceiverError(strconv.FormatInt(chatID, 10), fmt.Errorf("send message to chat %d: %w", chatID, err))
 		}
+
+		return nil
+	})
+}
+
+// Check verifies the API token by calling the getMe endpoint of the Bot API. It implements the notify.Checker interface.
+func (t Telegram) Check(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if _, err := t.client.GetMe(); err != nil {
+		return newReceiverError("", fmt.Errorf("get bot user: %w", err))
 	}
 
 	return nil
 }
+
+// newReceiverError wraps the given error into a notify.ReceiverError and classifies it based on the error description
+// returned by the Telegram Bot API. The library we use doesn't expose the numeric error codes, so we have to rely on
+// the descriptions, which are prefixed with the HTTP status text, e.g. "Forbidden: bot was blocked by the user".
+func newReceiverError(chatID string, err error) *notify.ReceiverError {
+	rErr := notify.NewReceiverError("telegram", chatID, err)
+
+	var apiErr tgbotapi.Error
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return rErr.SetKind(notify.ErrRateLimited)
+	}
+
+	desc := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(desc, "unauthorized"):
+		rErr.SetKind(notify.ErrAuthFailure)
+	case strings.Contains(desc, "too many requests"):
+		rErr.SetKind(notify.ErrRateLimited)
+	case strings.Contains(desc, "chat not found"),
+		strings.Contains(desc, "forbidden"),
+		strings.Contains(desc, "user is deactivated"),
+		strings.Contains(desc, "group chat was upgraded"):
+		rErr.SetKind(notify.ErrInvalidReceiver)
+	case strings.Contains(desc, "message is too long"):
+		rErr.SetKind(notify.ErrPayloadTooLarge)
+	}
+
+	return rErr
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	textMagic "github.com/textmagic/textmagic-rest-go-v2/v3"
+
+	"github.com/nikoksr/notify"
 )
 
 // Service allow you to configure a TextMagic SDK client.
@@ -51,7 +53,7 @@ func (s *Service) Send(ctx context.Context, subject, message string) error {
 		}).
 		Execute()
 	if err != nil {
-		return fmt.Errorf("send message: %w", err)
+		return notify.NewReceiverError("textmagic", phones, fmt.Errorf("send message: %w", err))
 	}
 
 	return nil
@@ -2,10 +2,14 @@ package twilio
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 
+	"github.com/kevinburke/rest/resterror"
 	"github.com/kevinburke/twilio-go"
+
+	"github.com/nikoksr/notify"
 )
 
 // Compile-time check that twilio.MessageService satisfies twilioClient interface.
@@ -54,10 +58,36 @@ func (s *Service) Send(ctx context.Context, subject, message string) error {
 
 			_, err := s.client.SendMessage(s.fromPhoneNumber, toPhoneNumber, body, []*url.URL{})
 			if err != nil {
-				return fmt.Errorf("send message to recipient %q: %w", toPhoneNumber, err)
+				return newReceiverError(toPhoneNumber, fmt.Errorf("send message to recipient %q: %w", toPhoneNumber, err))
 			}
 		}
 	}
 
 	return nil
 }
+
+// newReceiverError wraps the given error into a notify.ReceiverError and classifies it based on the errors returned by
+// the Twilio API. See https://www.twilio.com/docs/api/errors.
+func newReceiverError(toPhoneNumber string, err error) *notify.ReceiverError {
+	rErr := notify.NewReceiverError("twilio", toPhoneNumber, err)
+
+	var apiErr *resterror.Error
+	if !errors.As(err, &apiErr) {
+		return rErr
+	}
+
+	rErr.Code = apiErr.ID
+	switch apiErr.ID {
+	case "21211", "21214", "21610", "21612", "21614": // Invalid, unreachable, or unsubscribed "To" number
+		rErr.SetKind(notify.ErrInvalidReceiver)
+	case "20003": // Authentication error
+		rErr.SetKind(notify.ErrAuthFailure)
+	case "20429": // Too many requests
+		rErr.SetKind(notify.ErrRateLimited)
+	case "21617": // Message body exceeds the character limit
+		rErr.SetKind(notify.ErrPayloadTooLarge)
+	}
+	rErr.SetStatusCode(apiErr.Status)
+
+	return rErr
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/dghubble/oauth1"
 	"github.com/drswork/go-twitter/twitter"
+
+	"github.com/nikoksr/notify"
 )
 
 // Twitter struct holds necessary data to communicate with the Twitter API.
@@ -98,7 +100,7 @@ func (t Twitter) Send(ctx context.Context, subject, message string) error {
 			}
 
 			if _, _, err := t.client.DirectMessages.EventsNew(directMessageParams); err != nil {
-				return fmt.Errorf("send message to %q: %w", twitterID, err)
+				return notify.NewReceiverError("twitter", twitterID, fmt.Errorf("send message to %q: %w", twitterID, err))
 			}
 		}
 	}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	vb "github.com/mileusna/viber"
+
+	"github.com/nikoksr/notify"
 )
 
 type viberClient interface {
@@ -50,7 +52,11 @@ func (v *Viber) Send(ctx context.Context, subject, message string) error {
 			return ctx.Err()
 		default:
 			if _, err := v.Client.SendTextMessage(subscribedUserID, fullMessage); err != nil {
-				return fmt.Errorf("send message to user %q: %w", subscribedUserID, err)
+				return notify.NewReceiverError(
+					"viber",
+					subscribedUserID,
+					fmt.Errorf("send message to user %q: %w", subscribedUserID, err),
+				)
 			}
 		}
 	}
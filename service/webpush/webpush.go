@@ -10,6 +10,8 @@ import (
 	"net/http"
 
 	"github.com/SherClockHolmes/webpush-go"
+
+	"github.com/nikoksr/notify"
 )
 
 type (
@@ -58,6 +60,7 @@ var (
 type Service struct {
 	subscriptions []webpush.Subscription
 	options       webpush.Options
+	delivery      notify.DeliveryOptions
 }
 
 // New returns a new instance of the Service.
@@ -76,6 +79,12 @@ func (s *Service) AddReceivers(subscriptions ...Subscription) {
 	s.subscriptions = append(s.subscriptions, subscriptions...)
 }
 
+// SetDeliveryOptions configures how the Send method delivers a message to multiple subscriptions. By default, subscriptions are
+// processed sequentially and delivery stops at the first error.
+func (s *Service) SetDeliveryOptions(opts notify.DeliveryOptions) {
+	s.delivery = opts
+}
+
 // withOptions returns a new Options struct with the incoming options merged with the Service's options. The incoming
 // options take precedence, except for the VAPID keys. Existing VAPID keys are only replaced if the incoming VAPID keys
 // are not empty.
@@ -119,6 +128,26 @@ func dataFromContext(ctx context.Context) map[string]any {
 	return map[string]any{}
 }
 
+// urgencyFromContext translates the notify.Priority carried by the given context into a web push Urgency. It returns an
+// empty Urgency if the context carries no priority.
+func urgencyFromContext(ctx context.Context) Urgency {
+	priority, ok := notify.PriorityFromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	switch {
+	case priority <= notify.PriorityMin:
+		return UrgencyVeryLow
+	case priority == notify.PriorityLow:
+		return UrgencyLow
+	case priority == notify.PriorityDefault:
+		return UrgencyNormal
+	}
+
+	return UrgencyHigh
+}
+
 // payloadFromContext returns a json encoded byte array of the messagePayload payload that is ready to be sent to the
 // webpush endpoint. Internally, it uses the messagePayload and data from the context, and it combines it with the
 // subject and message arguments into a single messagePayload.
@@ -142,7 +171,7 @@ func payloadFromContext(ctx context.Context, subject, message string) ([]byte, e
 func (s *Service) send(ctx context.Context, message []byte, subscription *Subscription, options *Options) error {
 	res, err := webpush.SendNotificationWithContext(ctx, message, subscription, options)
 	if err != nil {
-		return fmt.Errorf("send notification: %w", err)
+		return notify.NewReceiverError("webpush", subscription.Endpoint, fmt.Errorf("send notification: %w", err))
 	}
 	defer res.Body.Close()
 
@@ -161,29 +190,32 @@ func (s *Service) send(ctx context.Context, message []byte, subscription *Subscr
 		err = fmt.Errorf("read response body: %w", err)
 	}
 
-	err = errors.Join(baseErr, err)
+	// Push services answer with 404 or 410 for expired subscriptions, which should be removed by the caller.
+	rErr := notify.NewReceiverError("webpush", subscription.Endpoint, errors.Join(baseErr, err))
 
-	return err
+	return rErr.SetStatusCode(res.StatusCode)
 }
 
 // Send sends a message to all the webpush subscriptions that have been added to the Service. The subject and message
 // arguments are the subject and message of the messagePayload payload. The context can be used to optionally add
-// options and data to the messagePayload payload. See the WithOptions and WithData functions.
+// options and data to the messagePayload payload. See the WithOptions and WithData functions. Unless the options set an
+// Urgency, it's derived from the notify.Priority carried by the context.
 func (s *Service) Send(ctx context.Context, subject, message string) error {
 	// Get the options from the context and merge them with the service's initial options
 	options := optionsFromContext(ctx)
 	options = s.withOptions(options)
 
+	// Explicitly set urgencies take precedence over the notification priority.
+	if options.Urgency == "" {
+		options.Urgency = urgencyFromContext(ctx)
+	}
+
 	payload, err := payloadFromContext(ctx, subject, message)
 	if err != nil {
 		return err
 	}
 
-	for _, subscription := range s.subscriptions {
-		if err = s.send(ctx, payload, &subscription, &options); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return notify.Deliver(ctx, s.delivery, s.subscriptions, func(ctx context.Context, subscription Subscription) error {
+		return s.send(ctx, payload, &subscription, &options)
+	})
 }
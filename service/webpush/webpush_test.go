@@ -12,6 +12,8 @@ import (
 
 	"github.com/SherClockHolmes/webpush-go"
 	"github.com/google/go-cmp/cmp"
+
+	"github.com/nikoksr/notify"
 )
 
 // Allows us to simulate an error returned from the server on a per-request basis.
@@ -672,3 +674,44 @@ func Test_payloadFromContext(t *testing.T) {
 		})
 	}
 }
+
+func Test_urgencyFromContext(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want Urgency
+	}{
+		{
+			name: "No priority",
+			ctx:  context.Background(),
+			want: "",
+		},
+		{
+			name: "Min priority",
+			ctx:  notify.WithPriority(context.Background(), notify.PriorityMin),
+			want: UrgencyVeryLow,
+		},
+		{
+			name: "Default priority",
+			ctx:  notify.WithPriority(context.Background(), notify.PriorityDefault),
+			want: UrgencyNormal,
+		},
+		{
+			name: "Critical severity",
+			ctx:  notify.WithSeverity(context.Background(), notify.SeverityCritical),
+			want: UrgencyHigh,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := urgencyFromContext(tt.ctx); got != tt.want {
+				t.Errorf("urgencyFromContext() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
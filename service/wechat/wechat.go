@@ -2,9 +2,11 @@ package wechat
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"html"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -13,6 +15,8 @@ import (
 	"github.com/silenceper/wechat/v2/officialaccount/config"
 	"github.com/silenceper/wechat/v2/officialaccount/message"
 	"github.com/silenceper/wechat/v2/util"
+
+	"github.com/nikoksr/notify"
 )
 
 const defaultTimeout = 20 * time.Second
@@ -38,6 +42,7 @@ type Service struct {
 	config         *Config
 	messageManager wechatMessageManager
 	userIDs        []string
+	delivery       notify.DeliveryOptions
 }
 
 // New returns a new instance of a WeChat notification service.
@@ -166,20 +171,49 @@ func (s *Service) AddReceivers(userIDs ...string) {
 	s.userIDs = append(s.userIDs, userIDs...)
 }
 
+// SetDeliveryOptions configures how the Send method delivers a message to multiple users. By default, users are
+// processed sequentially and delivery stops at the first error.
+func (s *Service) SetDeliveryOptions(opts notify.DeliveryOptions) {
+	s.delivery = opts
+}
+
 // Send takes a message subject and a message content and sends them to all previously set users.
 func (s *Service) Send(ctx context.Context, subject, content string) error {
-	for _, userID := range s.userIDs {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			text := fmt.Sprintf("%s\n%s", subject, content)
-			err := s.messageManager.Send(message.NewCustomerTextMessage(userID, text))
-			if err != nil {
-				return fmt.Errorf("send message to user %q: %w", userID, err)
-			}
+	text := fmt.Sprintf("%s\n%s", subject, content)
+
+	return notify.Deliver(ctx, s.delivery, s.userIDs, func(_ context.Context, userID string) error {
+		err := s.messageManager.Send(message.NewCustomerTextMessage(userID, text))
+		if err != nil {
+			return newReceiverError(userID, fmt.Errorf("send message to user %q: %w", userID, err))
 		}
+
+		return nil
+	})
+}
+
+// newReceiverError wraps the given error into a notify.ReceiverError and classifies it based on the global error codes
+// of the WeChat API. See https://developers.weixin.qq.com/doc/offiaccount/en/Getting_Started/Global_Return_Code.html.
+func newReceiverError(userID string, err error) *notify.ReceiverError {
+	rErr := notify.NewReceiverError("wechat", userID, err)
+
+	var apiErr *util.CommonError
+	if !errors.As(err, &apiErr) {
+		return rErr
+	}
+
+	rErr.Code = strconv.FormatInt(apiErr.ErrCode, 10)
+	switch apiErr.ErrCode {
+	case -1: // System busy
+		rErr.Retryable = true
+	case 40001, 40014, 41001, 42001: // Invalid or expired access token
+		rErr.SetKind(notify.ErrAuthFailure)
+	case 40003, 43004, 45015: // Invalid OpenID, user not subscribed, or out of the reply time window
+		rErr.SetKind(notify.ErrInvalidReceiver)
+	case 45002: // Content too long
+		rErr.SetKind(notify.ErrPayloadTooLarge)
+	case 45009, 45047: // API call limit reached
+		rErr.SetKind(notify.ErrRateLimited)
 	}
 
-	return nil
+	return rErr
 }
@@ -0,0 +1,103 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Severity describes how important a notification is. Services can be registered with a minimum severity, so that they
+// only receive notifications that are important enough.
+type Severity int
+
+// These are the supported severities, from least to most important.
+const (
+	SeverityDebug Severity = iota
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+// allSeverities is a severity that every service accepts, regardless of its minimum severity.
+const allSeverities = Severity(math.MaxInt)
+
+// DefaultSeverity is the severity of notifications that are sent without one, e.g. using Send.
+const DefaultSeverity = SeverityInfo
+
+// String returns the lower-case name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityDebug:
+		return "debug"
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	}
+
+	return fmt.Sprintf("severity(%d)", int(s))
+}
+
+// ParseSeverity returns the severity with the given name. The name is matched case-insensitively; "warn" and "crit" are
+// accepted as well.
+func ParseSeverity(name string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return SeverityDebug, nil
+	case "info":
+		return SeverityInfo, nil
+	case "warning", "warn":
+		return SeverityWarning, nil
+	case "error":
+		return SeverityError, nil
+	case "critical", "crit":
+		return SeverityCritical, nil
+	}
+
+	return 0, fmt.Errorf("unknown severity %q", name)
+}
+
+// severityKey is the context key for the severity of a notification.
+type severityKey struct{}
+
+// WithSeverity returns a copy of the given context that carries the given severity. Notify instances use it to decide
+// which services receive a notification, and services may use it to set their provider's native priority.
+func WithSeverity(ctx context.Context, severity Severity) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, severityKey{}, severity)
+}
+
+// SeverityFromContext returns the severity carried by the given context. It reports false if the context carries none.
+func SeverityFromContext(ctx context.Context) (Severity, bool) {
+	if ctx == nil {
+		return DefaultSeverity, false
+	}
+
+	severity, ok := ctx.Value(severityKey{}).(Severity)
+	if !ok {
+		return DefaultSeverity, false
+	}
+
+	return severity, true
+}
+
+// ServiceOption configures a service when it's registered with a Notify instance.
+type ServiceOption func(*serviceEntry)
+
+// MinSeverity is a ServiceOption that makes the service only receive notifications with at least the given severity.
+//
+//	E.g. notify.MinSeverity(notify.SeverityCritical) for services that page people in the middle of the night.
+func MinSeverity(severity Severity) ServiceOption {
+	return func(entry *serviceEntry) {
+		entry.minSeverity = severity
+	}
+}
@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSeverity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		want    Severity
+		wantErr bool
+	}{
+		{name: "debug", want: SeverityDebug},
+		{name: "Info", want: SeverityInfo},
+		{name: "warn", want: SeverityWarning},
+		{name: " error ", want: SeverityError},
+		{name: "CRITICAL", want: SeverityCritical},
+		{name: "fatal", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseSeverity(tt.name)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+
+			roundTrip, err := ParseSeverity(got.String())
+			require.NoError(t, err)
+			require.Equal(t, got, roundTrip)
+		})
+	}
+}
+
+func TestSeverityFromContext(t *testing.T) {
+	t.Parallel()
+
+	severity, ok := SeverityFromContext(context.Background())
+	require.False(t, ok)
+	require.Equal(t, DefaultSeverity, severity)
+
+	severity, ok = SeverityFromContext(WithSeverity(context.Background(), SeverityCritical))
+	require.True(t, ok)
+	require.Equal(t, SeverityCritical, severity)
+}
+
+func TestSendWithSeverity(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	everything := &recordingNotifier{}
+	pager := &recordingNotifier{}
+
+	n := New()
+	require.NoError(t, n.UseNamedService("chat", everything))
+	require.NoError(t, n.UseNamedService("pager", pager, MinSeverity(SeverityCritical)))
+	require.Equal(t, SeverityCritical, n.Services()[1].MinSeverity)
+
+	require.NoError(t, n.Send(ctx, "plain", "message"))
+	require.NoError(t, n.SendWithSeverity(ctx, SeverityError, "error", "message"))
+	require.NoError(t, n.SendWithSeverity(ctx, SeverityCritical, "critical", "message"))
+	require.NoError(t, n.Send(WithSeverity(ctx, SeverityCritical), "context", "message"))
+
+	require.Equal(t, []string{"plain", "error", "critical", "context"}, everything.subjects)
+	require.Equal(t, []string{"critical", "context"}, pager.subjects)
+
+	require.NoError(t, n.SetMinSeverity("chat", SeverityWarning))
+	require.NoError(t, n.SendWithSeverity(ctx, SeverityInfo, "info", "message"))
+	require.Len(t, everything.subjects, 4)
+
+	require.ErrorIs(t, n.SetMinSeverity("unknown", SeverityInfo), ErrServiceNotFound)
+}
@@ -0,0 +1,340 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// These are the defaults of SlogHandlerOptions.
+const (
+	defaultSlogGroupWindow  = time.Minute
+	defaultSlogRateLimit    = 10
+	defaultSlogRateInterval = time.Minute
+)
+
+// SlogHandlerOptions configure a SlogHandler.
+type SlogHandlerOptions struct {
+	// Level is the minimum level of the records that are sent as notifications. Defaults to slog.LevelError.
+	Level slog.Leveler
+
+	// GroupWindow is how long records with the same level and message are grouped after the first one was sent. Only
+	// the first record of a group is sent right away; the repetitions are summarized in a single notification at the
+	// end of the window. Defaults to one minute, a negative value disables grouping.
+	GroupWindow time.Duration
+
+	// RateLimit is the maximum number of notifications that are sent per RateInterval. Records beyond the limit are
+	// dropped, and their number is mentioned in the next notification. Defaults to 10, a negative value disables the
+	// rate limit.
+	RateLimit int
+
+	// RateInterval is the interval RateLimit applies to. Defaults to one minute.
+	RateInterval time.Duration
+
+	// OnError, if set, is called with the errors of notifications that failed to be sent. It must not log through the
+	// SlogHandler, as that could result in an endless loop.
+	OnError func(err error)
+}
+
+// logGroup is a group of records with the same level and message.
+type logGroup struct {
+	level   slog.Level
+	subject string
+	message string
+	repeats int
+	timer   *time.Timer
+}
+
+// slogState is the state shared by a SlogHandler and all handlers derived from it using WithAttrs and WithGroup.
+type slogState struct {
+	notifier Notifier
+	opts     SlogHandlerOptions
+	now      func() time.Time
+
+	mu          sync.Mutex
+	groups      map[string]*logGroup
+	windowStart time.Time
+	sent        int
+	dropped     int
+	flushed     bool
+
+	wg sync.WaitGroup
+}
+
+// SlogHandler is a slog.Handler that sends log records at or above a configured level as notifications, while passing
+// all records on to another handler, so that logs still reach their normal sink. The message of a record becomes the
+// subject of the notification and its attributes the body, one per line. The level of a record is mapped to the
+// Severity of the notification.
+//
+// Notifications are sent in the background and are grouped and rate limited, so that a log storm doesn't turn into a
+// notification storm. Call Flush before the program exits to not lose pending notifications; records that are handled
+// afterwards still reach the wrapped handler, but are no longer sent as notifications.
+//
+// The Notifier must not log through the SlogHandler, as that could result in an endless loop.
+type SlogHandler struct {
+	next   slog.Handler
+	state  *slogState
+	attrs  []string
+	prefix string
+}
+
+// Compile-time check to ensure SlogHandler implements slog.Handler.
+var _ slog.Handler = (*SlogHandler)(nil)
+
+// NewSlogHandler returns a new SlogHandler that passes all records on to the given handler and sends those at or above
+// the configured level to the given notifier.
+func NewSlogHandler(next slog.Handler, notifier Notifier, opts SlogHandlerOptions) *SlogHandler {
+	if opts.Level == nil {
+		opts.Level = slog.LevelError
+	}
+	if opts.GroupWindow == 0 {
+		opts.GroupWindow = defaultSlogGroupWindow
+	}
+	if opts.RateLimit == 0 {
+		opts.RateLimit = defaultSlogRateLimit
+	}
+	if opts.RateInterval <= 0 {
+		opts.RateInterval = defaultSlogRateInterval
+	}
+
+	return &SlogHandler{
+		next: next,
+		state: &slogState{
+			notifier: notifier,
+			opts:     opts,
+			now:      time.Now,
+			groups:   make(map[string]*logGroup),
+		},
+	}
+}
+
+// Enabled reports whether the wrapped handler handles records of the given level, or whether they are sent as
+// notifications.
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.state.opts.Level.Level() || h.next.Enabled(ctx, level)
+}
+
+// Handle passes the record on to the wrapped handler and sends it as notification if its level is high enough. It
+// returns the error of the wrapped handler; errors of notifications are reported through OnError.
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	var err error
+	if h.next.Enabled(ctx, record.Level) {
+		err = h.next.Handle(ctx, record)
+	}
+
+	if record.Level >= h.state.opts.Level.Level() {
+		h.state.record(ctx, record.Level, record.Message, h.render(record))
+	}
+
+	return err
+}
+
+// WithAttrs returns a new SlogHandler whose notifications include the given attributes.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	h2 := *h
+	h2.next = h.next.WithAttrs(attrs)
+	h2.attrs = h.attrs[:len(h.attrs):len(h.attrs)]
+	for _, attr := range attrs {
+		h2.attrs = appendAttr(h2.attrs, h.prefix, attr)
+	}
+
+	return &h2
+}
+
+// WithGroup returns a new SlogHandler that qualifies the keys of all following attributes with the given group name.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.next = h.next.WithGroup(name)
+	h2.prefix = h.prefix + name + "."
+
+	return &h2
+}
+
+// Flush sends the summaries of all open groups right away and waits until all notifications have been sent or the
+// given context is done. No notifications are sent after Flush was called.
+func (h *SlogHandler) Flush(ctx context.Context) error {
+	s := h.state
+
+	s.mu.Lock()
+	s.flushed = true
+	for key, group := range s.groups {
+		group.timer.Stop()
+		s.summarize(key)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// render returns the body of the notification for the given record: the attributes of the handler and the record, one
+// per line. Records without attributes use their message as body.
+func (h *SlogHandler) render(record slog.Record) string {
+	lines := h.attrs[:len(h.attrs):len(h.attrs)]
+	record.Attrs(func(attr slog.Attr) bool {
+		lines = appendAttr(lines, h.prefix, attr)
+		return true
+	})
+
+	if len(lines) == 0 {
+		return record.Message
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// appendAttr appends the given attribute as "key: value" line to the given lines. Groups are flattened, using dots to
+// separate the keys.
+func appendAttr(lines []string, prefix string, attr slog.Attr) []string {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return lines
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, a := range attr.Value.Group() {
+			lines = appendAttr(lines, prefix, a)
+		}
+
+		return lines
+	}
+
+	return append(lines, prefix+attr.Key+": "+attr.Value.String())
+}
+
+// severityOfLevel maps a slog level to a Severity. Levels above slog.LevelError, e.g. a custom fatal level, are
+// mapped to SeverityCritical.
+func severityOfLevel(level slog.Level) Severity {
+	switch {
+	case level < slog.LevelInfo:
+		return SeverityDebug
+	case level < slog.LevelWarn:
+		return SeverityInfo
+	case level < slog.LevelError:
+		return SeverityWarning
+	case level == slog.LevelError:
+		return SeverityError
+	}
+
+	return SeverityCritical
+}
+
+// record sends the given record as notification, unless it belongs to an open group or exceeds the rate limit.
+func (s *slogState) record(ctx context.Context, level slog.Level, subject, message string) {
+	key := level.String() + "\x00" + subject
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.flushed {
+		return
+	}
+
+	if s.opts.GroupWindow > 0 {
+		if group, ok := s.groups[key]; ok {
+			group.repeats++
+			group.message = message
+
+			return
+		}
+
+		s.groups[key] = &logGroup{
+			level:   level,
+			subject: subject,
+			timer:   time.AfterFunc(s.opts.GroupWindow, func() { s.closeGroup(key) }),
+		}
+	}
+	if message, ok := s.allow(message); ok {
+		s.send(ctx, level, subject, message)
+	}
+}
+
+// closeGroup removes the group with the given key and sends a summary of its repetitions, if any, unless the handler
+// was flushed in the meantime.
+func (s *slogState) closeGroup(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.flushed {
+		s.summarize(key)
+	}
+}
+
+// summarize removes the group with the given key and sends a summary of its repetitions, if any. The caller must hold
+// the lock.
+func (s *slogState) summarize(key string) {
+	group, ok := s.groups[key]
+	delete(s.groups, key)
+	if !ok || group.repeats == 0 {
+		return
+	}
+
+	subject := fmt.Sprintf("%s (repeated %d times)", group.subject, group.repeats)
+	if message, ok := s.allow(group.message); ok {
+		s.send(context.Background(), group.level, subject, message)
+	}
+}
+
+// allow reports whether another notification may be sent within the rate limit. If so, the number of notifications
+// that were dropped before is appended to the given message. The caller must hold the lock.
+func (s *slogState) allow(message string) (string, bool) {
+	if s.opts.RateLimit < 0 {
+		return message, true
+	}
+
+	now := s.now()
+	if now.Sub(s.windowStart) >= s.opts.RateInterval {
+		s.windowStart = now
+		s.sent = 0
+	}
+
+	if s.sent >= s.opts.RateLimit {
+		s.dropped++
+		return message, false
+	}
+	s.sent++
+
+	if s.dropped > 0 {
+		message += fmt.Sprintf("\n\n%d notifications were dropped by the rate limit.", s.dropped)
+		s.dropped = 0
+	}
+
+	return message, true
+}
+
+// send sends the notification in the background. The cancellation of the given context is ignored, as the record
+// usually outlives the request it was logged for. The caller must hold the lock, so that Flush doesn't miss the
+// notification while waiting.
+func (s *slogState) send(ctx context.Context, level slog.Level, subject, message string) {
+	ctx = WithSeverity(context.WithoutCancel(ctx), severityOfLevel(level))
+
+	s.wg.Go(func() {
+		if err := s.notifier.Send(ctx, subject, message); err != nil && s.opts.OnError != nil {
+			s.opts.OnError(fmt.Errorf("send log notification: %w", err))
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type logNotification struct {
+	subject  string
+	message  string
+	severity Severity
+}
+
+type logNotifier chan logNotification
+
+func (l logNotifier) Send(ctx context.Context, subject, message string) error {
+	severity, _ := SeverityFromContext(ctx)
+	l <- logNotification{subject: subject, message: message, severity: severity}
+
+	return nil
+}
+
+func receiveLog(t *testing.T, ch logNotifier) logNotification {
+	t.Helper()
+
+	select {
+	case n := <-ch:
+		return n
+	case <-time.After(time.Second):
+		t.Fatal("no notification received")
+		return logNotification{}
+	}
+}
+
+func requireNoLog(t *testing.T, ch logNotifier) {
+	t.Helper()
+
+	select {
+	case n := <-ch:
+		t.Fatalf("unexpected notification: %s", n.subject)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	ch := make(logNotifier, 10)
+	handler := NewSlogHandler(slog.NewTextHandler(&buf, nil), ch, SlogHandlerOptions{})
+	logger := slog.New(handler)
+
+	logger.Info("server started", "port", 8080)
+	requireNoLog(t, ch)
+
+	logger.With("service", "api").WithGroup("req").Error("request failed",
+		"path", "/users",
+		slog.Group("user", "id", 42),
+	)
+
+	got := receiveLog(t, ch)
+	require.Equal(t, "request failed", got.subject)
+	require.Equal(t, "service: api\nreq.path: /users\nreq.user.id: 42", got.message)
+	require.Equal(t, SeverityError, got.severity)
+
+	// All records reach the wrapped handler.
+	require.Contains(t, buf.String(), "server started")
+	require.Contains(t, buf.String(), "request failed")
+
+	require.NoError(t, handler.Flush(context.Background()))
+}
+
+func TestSlogHandler_Level(t *testing.T) {
+	t.Parallel()
+
+	ch := make(logNotifier, 10)
+	next := slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelError})
+	handler := NewSlogHandler(next, ch, SlogHandlerOptions{Level: slog.LevelWarn})
+	logger := slog.New(handler)
+
+	require.True(t, handler.Enabled(context.Background(), slog.LevelWarn))
+	require.False(t, handler.Enabled(context.Background(), slog.LevelInfo))
+
+	logger.Warn("disk almost full")
+	got := receiveLog(t, ch)
+	require.Equal(t, "disk almost full", got.message)
+	require.Equal(t, SeverityWarning, got.severity)
+
+	logger.Log(context.Background(), slog.LevelError+4, "out of memory")
+	require.Equal(t, SeverityCritical, receiveLog(t, ch).severity)
+}
+
+func TestSlogHandler_Grouping(t *testing.T) {
+	t.Parallel()
+
+	ch := make(logNotifier, 10)
+	handler := NewSlogHandler(slog.DiscardHandler, ch, SlogHandlerOptions{GroupWindow: 100 * time.Millisecond})
+	logger := slog.New(handler)
+
+	for i := range 3 {
+		logger.Error("connection lost", "attempt", i)
+	}
+	logger.Error("disk full")
+
+	first := receiveLog(t, ch)
+	second := receiveLog(t, ch)
+	require.ElementsMatch(t, []string{"connection lost", "disk full"}, []string{first.subject, second.subject})
+
+	summary := receiveLog(t, ch)
+	require.Equal(t, "connection lost (repeated 2 times)", summary.subject)
+	require.Equal(t, "attempt: 2", summary.message)
+	require.Equal(t, SeverityError, summary.severity)
+	requireNoLog(t, ch)
+
+	// The group is closed, so the next record is sent right away.
+	logger.Error("connection lost")
+	require.Equal(t, "connection lost", receiveLog(t, ch).subject)
+}
+
+func TestSlogHandler_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	ch := make(logNotifier, 10)
+	handler := NewSlogHandler(slog.DiscardHandler, ch, SlogHandlerOptions{
+		GroupWindow: -1,
+		RateLimit:   2,
+	})
+	now := time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC)
+	handler.state.now = func() time.Time { return now }
+	logger := slog.New(handler)
+
+	for range 5 {
+		logger.Error("boom")
+	}
+	receiveLog(t, ch)
+	receiveLog(t, ch)
+	requireNoLog(t, ch)
+
+	now = now.Add(time.Minute)
+	logger.Error("boom")
+	require.Equal(t, "boom\n\n3 notifications were dropped by the rate limit.", receiveLog(t, ch).message)
+}
+
+func TestSlogHandler_Flush(t *testing.T) {
+	t.Parallel()
+
+	ch := make(logNotifier, 10)
+	handler := NewSlogHandler(slog.DiscardHandler, ch, SlogHandlerOptions{GroupWindow: time.Hour})
+	logger := slog.New(handler)
+
+	logger.Error("boom")
+	logger.Error("boom")
+	require.Equal(t, "boom", receiveLog(t, ch).subject)
+
+	require.NoError(t, handler.Flush(context.Background()))
+	require.Equal(t, "boom (repeated 1 times)", receiveLog(t, ch).subject)
+	require.Empty(t, handler.state.groups)
+}
+
+func TestSlogHandler_ConcurrentFlush(t *testing.T) {
+	t.Parallel()
+
+	ch := make(logNotifier, 100)
+	handler := NewSlogHandler(slog.DiscardHandler, ch, SlogHandlerOptions{GroupWindow: -1, RateLimit: -1})
+	logger := slog.New(handler)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			for range 10 {
+				logger.Error("boom")
+			}
+		})
+	}
+	require.NoError(t, handler.Flush(context.Background()))
+	wg.Wait()
+
+	// Records that are handled after Flush are no longer sent.
+	sent := len(ch)
+	logger.Error("boom")
+	require.NoError(t, handler.Flush(context.Background()))
+	require.Len(t, ch, sent)
+}
@@ -1,25 +1,301 @@
 package notify
 
-// useService adds a given service to the Notifier's services list.
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	// ErrServiceExists signals that a service with the given name is already registered.
+	ErrServiceExists = errors.New("service already exists")
+
+	// ErrServiceNotFound signals that no service with the given name is registered.
+	ErrServiceNotFound = errors.New("service not found")
+
+	// ErrEmptyServiceName signals that a service was registered without a name.
+	ErrEmptyServiceName = errors.New("service name is empty")
+)
+
+// serviceEntry is a service registered with a Notify instance.
+type serviceEntry struct {
+	name        string
+	service     Notifier
+	disabled    bool
+	minSeverity Severity
+}
+
+// ServiceInfo describes a service that is registered with a Notify instance.
+type ServiceInfo struct {
+	// Name is the unique name of the service. Services added without a name get one generated from their type, e.g.
+	// "*slack.Slack" or "*slack.Slack#2".
+	Name string
+
+	// Service is the registered notification service.
+	Service Notifier
+
+	// Disabled reports whether the service is currently skipped when sending.
+	Disabled bool
+
+	// MinSeverity is the minimum severity a notification needs to be sent to the service.
+	MinSeverity Severity
+}
+
+// NamedService is a service together with its name and options, as passed to ReplaceServices.
+type NamedService struct {
+	// Name is the unique name of the service.
+	Name string
+
+	// Service is the notification service.
+	Service Notifier
+
+	// Options are applied to the service when it's registered, e.g. MinSeverity.
+	Options []ServiceOption
+}
+
+// newServiceEntry returns an entry for the given named service with the given options applied.
+func newServiceEntry(name string, service Notifier, options []ServiceOption) *serviceEntry {
+	entry := &serviceEntry{name: name, service: service}
+	for _, option := range options {
+		if option != nil {
+			option(entry)
+		}
+	}
+
+	return entry
+}
+
+// indexOf returns the index of the service with the given name, or -1 if there is none. The caller must hold the lock.
+func (n *Notify) indexOf(name string) int {
+	for i, entry := range n.services {
+		if entry.name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// generateName returns a unique name for the given service based on its type. The caller must hold the lock.
+func (n *Notify) generateName(service Notifier) string {
+	base := fmt.Sprintf("%T", service)
+
+	name := base
+	for i := 2; n.indexOf(name) >= 0; i++ {
+		name = fmt.Sprintf("%s#%d", base, i)
+	}
+
+	return name
+}
+
+// useService adds a given service to the Notifier's services list. The caller must hold the lock.
 func (n *Notify) useService(service Notifier) {
 	if service != nil {
-		n.notifiers = append(n.notifiers, service)
+		n.services = append(n.services, &serviceEntry{name: n.generateName(service), service: service})
 	}
 }
 
 // useServices adds the given service(s) to the Notifier's services list.
 func (n *Notify) useServices(services ...Notifier) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
 	for _, s := range services {
 		n.useService(s)
 	}
 }
 
-// UseServices adds the given service(s) to the Notifier's services list.
+// UseServices adds the given service(s) to the Notifier's services list. Each service gets a name generated from its
+// type; use UseNamedService to choose the name yourself.
 func (n *Notify) UseServices(services ...Notifier) {
 	n.useServices(services...)
 }
 
+// UseNamedService adds the given service under the given name and applies the given options to it. The name can later
+// be used to remove, enable or disable the service. It returns ErrServiceExists if the name is already taken.
+func (n *Notify) UseNamedService(name string, service Notifier, options ...ServiceOption) error {
+	if name == "" {
+		return ErrEmptyServiceName
+	}
+	if service == nil {
+		return nil
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.indexOf(name) >= 0 {
+		return fmt.Errorf("%w: %q", ErrServiceExists, name)
+	}
+	n.services = append(n.services, newServiceEntry(name, service, options))
+
+	return nil
+}
+
+// RemoveService removes the service with the given name. It reports whether a service was removed.
+func (n *Notify) RemoveService(name string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	i := n.indexOf(name)
+	if i < 0 {
+		return false
+	}
+
+	n.services = slices.Delete(n.services, i, i+1)
+
+	return true
+}
+
+// ReplaceServices atomically replaces all registered services with the given named services, in the given order, and
+// applies their options like UseNamedService. Sends that are already in progress keep using the previous set of
+// services. Nil services are skipped. It returns ErrServiceExists if a name is used more than once, in which case the
+// registered services are left unchanged.
+func (n *Notify) ReplaceServices(services ...NamedService) error {
+	entries := make([]*serviceEntry, 0, len(services))
+	names := make(map[string]struct{}, len(services))
+	for _, svc := range services {
+		if svc.Name == "" {
+			return ErrEmptyServiceName
+		}
+		if _, ok := names[svc.Name]; ok {
+			return fmt.Errorf("%w: %q", ErrServiceExists, svc.Name)
+		}
+		names[svc.Name] = struct{}{}
+
+		if svc.Service != nil {
+			entries = append(entries, newServiceEntry(svc.Name, svc.Service, svc.Options))
+		}
+	}
+
+	n.mu.Lock()
+	n.services = entries
+	n.mu.Unlock()
+
+	return nil
+}
+
+// Services returns a description of all registered services in the order they were registered.
+func (n *Notify) Services() []ServiceInfo {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	infos := make([]ServiceInfo, 0, len(n.services))
+	for _, entry := range n.services {
+		infos = append(infos, ServiceInfo{
+			Name:        entry.name,
+			Service:     entry.service,
+			Disabled:    entry.disabled,
+			MinSeverity: entry.minSeverity,
+		})
+	}
+
+	return infos
+}
+
+// updateService applies the given update to the service with the given name.
+func (n *Notify) updateService(name string, update func(entry *serviceEntry)) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	i := n.indexOf(name)
+	if i < 0 {
+		return fmt.Errorf("%w: %q", ErrServiceNotFound, name)
+	}
+
+	update(n.services[i])
+
+	return nil
+}
+
+// EnableService enables the service with the given name, so that it receives messages again.
+func (n *Notify) EnableService(name string) error {
+	return n.updateService(name, func(entry *serviceEntry) { entry.disabled = false })
+}
+
+// DisableService disables the service with the given name. Disabled services stay registered but are skipped when
+// sending, until they get enabled again.
+func (n *Notify) DisableService(name string) error {
+	return n.updateService(name, func(entry *serviceEntry) { entry.disabled = true })
+}
+
+// Disabled is a ServiceOption that registers the service disabled, e.g. to keep a disabled service disabled when
+// replacing the services. Use EnableService to enable it.
+func Disabled() ServiceOption {
+	return func(entry *serviceEntry) {
+		entry.disabled = true
+	}
+}
+
+// SetMinSeverity sets the minimum severity a notification needs to be sent to the service with the given name.
+func (n *Notify) SetMinSeverity(name string, severity Severity) error {
+	return n.updateService(name, func(entry *serviceEntry) { entry.minSeverity = severity })
+}
+
+// enabledEntries returns a snapshot of all enabled services that accept notifications of the given severity.
+func (n *Notify) enabledEntries(severity Severity) []serviceEntry {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	entries := make([]serviceEntry, 0, len(n.services))
+	for _, entry := range n.services {
+		if entry.service != nil && !entry.disabled && severity >= entry.minSeverity {
+			entries = append(entries, *entry)
+		}
+	}
+
+	return entries
+}
+
+// enabledServices returns a snapshot of all enabled services that accept notifications of the given severity.
+func (n *Notify) enabledServices(severity Severity) []Notifier {
+	entries := n.enabledEntries(severity)
+
+	services := make([]Notifier, 0, len(entries))
+	for _, entry := range entries {
+		services = append(services, entry.service)
+	}
+
+	return services
+}
+
 // UseServices adds the given service(s) to the Notifier's services list.
 func UseServices(services ...Notifier) {
 	std.UseServices(services...)
 }
+
+// UseNamedService adds the given service under the given name and applies the given options to it.
+func UseNamedService(name string, service Notifier, options ...ServiceOption) error {
+	return std.UseNamedService(name, service, options...)
+}
+
+// RemoveService removes the service with the given name. It reports whether a service was removed.
+func RemoveService(name string) bool {
+	return std.RemoveService(name)
+}
+
+// ReplaceServices atomically replaces all registered services with the given named services, in the given order.
+func ReplaceServices(services ...NamedService) error {
+	return std.ReplaceServices(services...)
+}
+
+// Services returns a description of all registered services in the order they were registered.
+func Services() []ServiceInfo {
+	return std.Services()
+}
+
+// EnableService enables the service with the given name, so that it receives messages again.
+func EnableService(name string) error {
+	return std.EnableService(name)
+}
+
+// DisableService disables the service with the given name. Disabled services stay registered but are skipped when
+// sending, until they get enabled again.
+func DisableService(name string) error {
+	return std.DisableService(name)
+}
+
+// SetMinSeverity sets the minimum severity a notification needs to be sent to the service with the given name.
+func SetMinSeverity(name string, severity Severity) error {
+	return std.SetMinSeverity(name, severity)
+}
@@ -1,32 +1,36 @@
 package notify
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 
-	"github.com/nikoksr/notify/service/mail"
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestUseServices(t *testing.T) {
 	t.Parallel()
 
 	n := New()
-	if len(n.notifiers) != 0 {
-		t.Fatalf("Expected len(n.notifiers) == 0, got %d", len(n.notifiers))
+	if len(n.services) != 0 {
+		t.Fatalf("Expected len(n.services) == 0, got %d", len(n.services))
 	}
 
-	n.UseServices(mail.New("", ""))
+	n.UseServices(newFailingNotifier())
 
-	if len(n.notifiers) != 1 {
-		t.Errorf("Expected len(n.notifiers) == 1, got %d", len(n.notifiers))
+	if len(n.services) != 1 {
+		t.Errorf("Expected len(n.services) == 1, got %d", len(n.services))
 	}
 
 	n.UseServices(
-		mail.New("", ""),
-		mail.New("", ""),
+		newFailingNotifier(),
+		newFailingNotifier(),
 	)
 
-	if len(n.notifiers) != 3 {
-		t.Errorf("Expected len(n.notifiers) == 3, got %d", len(n.notifiers))
+	if len(n.services) != 3 {
+		t.Errorf("Expected len(n.services) == 3, got %d", len(n.services))
 	}
 
 	n.UseServices(nil)
@@ -34,3 +38,139 @@ func TestUseServices(t *testing.T) {
 		t.Errorf("Expected no panic, got %v", r)
 	}
 }
+
+func TestUseNamedService(t *testing.T) {
+	t.Parallel()
+
+	n := New()
+
+	if err := n.UseNamedService("", newFailingNotifier()); !errors.Is(err, ErrEmptyServiceName) {
+		t.Errorf("Expected ErrEmptyServiceName, got %v", err)
+	}
+	if err := n.UseNamedService("mail", newFailingNotifier()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := n.UseNamedService("mail", newFailingNotifier()); !errors.Is(err, ErrServiceExists) {
+		t.Errorf("Expected ErrServiceExists, got %v", err)
+	}
+
+	n.UseServices(newFailingNotifier(), newFailingNotifier())
+
+	var names []string
+	for _, info := range n.Services() {
+		names = append(names, info.Name)
+	}
+	want := []string{"mail", "*notify.failingNotifier", "*notify.failingNotifier#2"}
+	if diff := cmp.Diff(want, names); diff != "" {
+		t.Errorf("Unexpected service names:\n%s", diff)
+	}
+}
+
+func TestRemoveService(t *testing.T) {
+	t.Parallel()
+
+	n := New()
+	_ = n.UseNamedService("a", newFailingNotifier())
+	_ = n.UseNamedService("b", newFailingNotifier())
+
+	if !n.RemoveService("a") {
+		t.Error("Expected RemoveService(\"a\") to remove a service")
+	}
+	if n.RemoveService("a") {
+		t.Error("Expected second RemoveService(\"a\") to remove nothing")
+	}
+
+	services := n.Services()
+	if len(services) != 1 || services[0].Name != "b" {
+		t.Errorf("Expected only service \"b\" to be left, got %v", services)
+	}
+}
+
+func TestReplaceServices(t *testing.T) {
+	t.Parallel()
+
+	n := NewWithServices(newFailingNotifier())
+
+	if err := n.ReplaceServices(NamedService{Service: newFailingNotifier()}); !errors.Is(err, ErrEmptyServiceName) {
+		t.Errorf("Expected ErrEmptyServiceName, got %v", err)
+	}
+	err := n.ReplaceServices(
+		NamedService{Name: "a", Service: newFailingNotifier()},
+		NamedService{Name: "a", Service: newFailingNotifier()},
+	)
+	if !errors.Is(err, ErrServiceExists) {
+		t.Errorf("Expected ErrServiceExists, got %v", err)
+	}
+	if len(n.Services()) != 1 {
+		t.Fatal("Failed ReplaceServices() altered the services")
+	}
+
+	err = n.ReplaceServices(
+		NamedService{Name: "b", Service: newFailingNotifier(), Options: []ServiceOption{MinSeverity(SeverityError)}},
+		NamedService{Name: "a", Service: newFailingNotifier(), Options: []ServiceOption{Disabled()}},
+		NamedService{Name: "nil"},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	services := n.Services()
+	if len(services) != 2 || services[0].Name != "b" || services[1].Name != "a" {
+		t.Fatalf("Expected services \"b\" and \"a\" in the given order, got %v", services)
+	}
+	if services[0].MinSeverity != SeverityError || services[1].MinSeverity != SeverityDebug || services[0].Disabled ||
+		!services[1].Disabled {
+		t.Errorf("Expected the options to be applied, got %v", services)
+	}
+}
+
+func TestDisableService(t *testing.T) {
+	t.Parallel()
+
+	n := New()
+	_ = n.UseNamedService("failing", newFailingNotifier())
+
+	if err := n.DisableService("unknown"); !errors.Is(err, ErrServiceNotFound) {
+		t.Errorf("Expected ErrServiceNotFound, got %v", err)
+	}
+
+	if err := n.DisableService("failing"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !n.Services()[0].Disabled {
+		t.Error("Expected service to be disabled")
+	}
+	if err := n.Send(context.Background(), "subject", "message"); err != nil {
+		t.Errorf("Send() with disabled service returned error: %v", err)
+	}
+
+	if err := n.EnableService("failing"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := n.Send(context.Background(), "subject", "message"); err == nil {
+		t.Error("Send() with enabled failing service returned no error")
+	}
+}
+
+func TestServicesConcurrentAccess(t *testing.T) {
+	t.Parallel()
+
+	n := New()
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Go(func() {
+			name := fmt.Sprintf("service-%d", i)
+			_ = n.UseNamedService(name, newFailingNotifier())
+			_ = n.Send(context.Background(), "subject", "message")
+			_ = n.DisableService(name)
+			_ = n.Services()
+			n.RemoveService(name)
+		})
+	}
+	wg.Wait()
+
+	if len(n.Services()) != 0 {
+		t.Errorf("Expected no services to be left, got %d", len(n.Services()))
+	}
+}
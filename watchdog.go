@@ -0,0 +1,322 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUnknownHeartbeat signals that no heartbeat with the given name is configured.
+var ErrUnknownHeartbeat = errors.New("unknown heartbeat")
+
+// Heartbeat is a named signal that a job is expected to send regularly by calling Watchdog.Ping.
+type Heartbeat struct {
+	// Name uniquely identifies the heartbeat, e.g. "nightly-backup".
+	Name string
+
+	// Interval is how often the job is expected to ping.
+	Interval time.Duration
+
+	// Grace is how long a ping may be late before the heartbeat is considered missed.
+	Grace time.Duration
+}
+
+// HeartbeatStatus is the current state of a heartbeat.
+type HeartbeatStatus struct {
+	// Name is the name of the heartbeat.
+	Name string
+
+	// LastSeen is the time of the last ping. It's zero if the heartbeat was never seen.
+	LastSeen time.Time
+
+	// Down reports whether the heartbeat was missed and has not recovered yet.
+	Down bool
+}
+
+// HeartbeatStore persists the time every heartbeat was last seen, so that missed heartbeats are detected across
+// restarts of the process.
+type HeartbeatStore interface {
+	// Save stores the time the heartbeat with the given name was last seen.
+	Save(ctx context.Context, name string, lastSeen time.Time) error
+
+	// Load returns the times all stored heartbeats were last seen, keyed by their names.
+	Load(ctx context.Context) (map[string]time.Time, error)
+}
+
+// WatchdogOptions configure a Watchdog.
+type WatchdogOptions struct {
+	// Heartbeats are the heartbeats that are watched.
+	Heartbeats []Heartbeat
+
+	// Store, if set, persists the times the heartbeats were last seen.
+	Store HeartbeatStore
+
+	// OnError, if set, is called with the errors of notifications that failed to be sent in the background.
+	OnError func(name string, err error)
+}
+
+// heartbeat is a watched heartbeat together with its state.
+type heartbeat struct {
+	Heartbeat
+
+	lastSeen time.Time
+	down     bool
+	timer    *time.Timer
+}
+
+// Watchdog is a dead man's switch: jobs call Ping regularly, and if a heartbeat is not seen within its interval plus
+// its grace period, a notification is sent. Once pings resume, a recovery notification follows. Missed heartbeats are
+// reported with SeverityError, recoveries with SeverityInfo.
+//
+// A heartbeat that was never seen is expected within its interval after the watchdog was started. A Watchdog is safe
+// for concurrent use.
+type Watchdog struct {
+	notifier Notifier
+	opts     WatchdogOptions
+	now      func() time.Time
+
+	mu         sync.Mutex
+	heartbeats map[string]*heartbeat
+	startedAt  time.Time
+	stopped    bool
+
+	wg sync.WaitGroup
+}
+
+// NewWatchdog returns a new Watchdog that sends its notifications to the given notifier, usually a *Notify. It doesn't
+// watch the heartbeats until Start is called.
+func NewWatchdog(notifier Notifier, opts WatchdogOptions) *Watchdog {
+	w := &Watchdog{
+		notifier:   notifier,
+		opts:       opts,
+		now:        time.Now,
+		heartbeats: make(map[string]*heartbeat, len(opts.Heartbeats)),
+	}
+	for _, hb := range opts.Heartbeats {
+		w.heartbeats[hb.Name] = &heartbeat{Heartbeat: hb}
+	}
+
+	return w
+}
+
+// Start loads the times the heartbeats were last seen from the store, if any, and starts watching them. Heartbeats
+// that were already missed while the process wasn't running are reported right away.
+func (w *Watchdog) Start(ctx context.Context) error {
+	var lastSeen map[string]time.Time
+	if w.opts.Store != nil {
+		var err error
+		if lastSeen, err = w.opts.Store.Load(ctx); err != nil {
+			return fmt.Errorf("load heartbeats: %w", err)
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.startedAt = w.now()
+	for name, hb := range w.heartbeats {
+		if t, ok := lastSeen[name]; ok && t.After(hb.lastSeen) {
+			hb.lastSeen = t
+		}
+		w.arm(hb)
+	}
+
+	return nil
+}
+
+// lastSignal returns the time of the last ping of the given heartbeat, or the start of the watchdog if it was never
+// seen. The caller must hold the lock.
+func (w *Watchdog) lastSignal(hb *heartbeat) time.Time {
+	if hb.lastSeen.IsZero() {
+		return w.startedAt
+	}
+
+	return hb.lastSeen
+}
+
+// deadline returns the point in time at which the given heartbeat is missed. The caller must hold the lock.
+func (w *Watchdog) deadline(hb *heartbeat) time.Time {
+	return w.lastSignal(hb).Add(hb.Interval + hb.Grace)
+}
+
+// arm (re)starts the timer that reports the given heartbeat as missed. The caller must hold the lock.
+func (w *Watchdog) arm(hb *heartbeat) {
+	if hb.timer != nil {
+		hb.timer.Stop()
+	}
+
+	name := hb.Name
+	hb.timer = time.AfterFunc(w.deadline(hb).Sub(w.now()), func() { w.expire(name) })
+}
+
+// expire reports the heartbeat with the given name as missed, unless it was seen in the meantime.
+func (w *Watchdog) expire(name string) {
+	w.mu.Lock()
+	hb, ok := w.heartbeats[name]
+	if !ok || hb.down || w.stopped {
+		w.mu.Unlock()
+		return
+	}
+
+	deadline := w.deadline(hb)
+	if now := w.now(); now.Before(deadline) {
+		w.arm(hb) // Pinged while the timer fired.
+		w.mu.Unlock()
+
+		return
+	}
+	hb.down = true
+
+	message := fmt.Sprintf("No ping received since the watchdog started at %s.", w.startedAt.Format(time.RFC3339))
+	if !hb.lastSeen.IsZero() {
+		message = fmt.Sprintf("No ping received since %s.", hb.lastSeen.Format(time.RFC3339))
+	}
+	message += fmt.Sprintf(" Expected every %s with a grace period of %s.", hb.Interval, hb.Grace)
+
+	// Start the notification while holding the lock, so that Stop doesn't miss it while waiting.
+	w.wg.Go(func() { w.send(name, SeverityError, fmt.Sprintf("Heartbeat %q missed", name), message) })
+	w.mu.Unlock()
+}
+
+// Ping records that the heartbeat with the given name was seen. If the heartbeat was missed before, a recovery
+// notification is sent in the background, unless the watchdog was stopped. It returns ErrUnknownHeartbeat for
+// heartbeats that are not configured, and the error of the store, if any.
+func (w *Watchdog) Ping(name string) error {
+	w.mu.Lock()
+	hb, ok := w.heartbeats[name]
+	if !ok {
+		w.mu.Unlock()
+		return fmt.Errorf("%w: %q", ErrUnknownHeartbeat, name)
+	}
+
+	now := w.now()
+	recovered, silence := hb.down, now.Sub(w.lastSignal(hb))
+	hb.down = false
+	hb.lastSeen = now
+	if !w.startedAt.IsZero() && !w.stopped {
+		w.arm(hb)
+	}
+	if recovered && !w.stopped {
+		w.wg.Go(func() {
+			w.send(name, SeverityInfo, fmt.Sprintf("Heartbeat %q recovered", name),
+				fmt.Sprintf("Ping received at %s after %s of silence.", now.Format(time.RFC3339), silence.Round(time.Second)))
+		})
+	}
+	w.mu.Unlock()
+
+	if w.opts.Store != nil {
+		if err := w.opts.Store.Save(context.Background(), name, now); err != nil {
+			return fmt.Errorf("save heartbeat %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// send sends a notification about the heartbeat with the given name and reports errors through OnError.
+func (w *Watchdog) send(name string, severity Severity, subject, message string) {
+	err := w.notifier.Send(WithSeverity(context.Background(), severity), subject, message)
+	if err != nil && w.opts.OnError != nil {
+		w.opts.OnError(name, err)
+	}
+}
+
+// Status returns the current state of all heartbeats, ordered by their names.
+func (w *Watchdog) Status() []HeartbeatStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	statuses := make([]HeartbeatStatus, 0, len(w.heartbeats))
+	for _, hb := range w.heartbeats {
+		statuses = append(statuses, HeartbeatStatus{Name: hb.Name, LastSeen: hb.lastSeen, Down: hb.down})
+	}
+	slices.SortFunc(statuses, func(a, b HeartbeatStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return statuses
+}
+
+// Stop stops watching the heartbeats and waits for pending notifications to be sent. No notifications are sent after
+// Stop was called.
+func (w *Watchdog) Stop() {
+	w.mu.Lock()
+	w.stopped = true
+	for _, hb := range w.heartbeats {
+		if hb.timer != nil {
+			hb.timer.Stop()
+		}
+	}
+	w.mu.Unlock()
+
+	w.wg.Wait()
+}
+
+// FileHeartbeatStore is a HeartbeatStore that keeps the times all heartbeats were last seen in a single JSON file.
+type FileHeartbeatStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// Compile-time check to ensure FileHeartbeatStore implements HeartbeatStore.
+var _ HeartbeatStore = (*FileHeartbeatStore)(nil)
+
+// NewFileHeartbeatStore returns a new FileHeartbeatStore that uses the file at the given path. The file is created on
+// the first write.
+func NewFileHeartbeatStore(path string) *FileHeartbeatStore {
+	return &FileHeartbeatStore{path: path}
+}
+
+// read returns all times in the file. The caller must hold the lock.
+func (f *FileHeartbeatStore) read() (map[string]time.Time, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string]time.Time), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lastSeen := make(map[string]time.Time)
+	if err = json.Unmarshal(data, &lastSeen); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", f.path, err)
+	}
+
+	return lastSeen, nil
+}
+
+// Save stores the time the heartbeat with the given name was last seen. Older times don't overwrite newer ones.
+func (f *FileHeartbeatStore) Save(_ context.Context, name string, lastSeen time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	times, err := f.read()
+	if err != nil {
+		return err
+	}
+	if lastSeen.Before(times[name]) {
+		return nil
+	}
+	times[name] = lastSeen
+
+	data, err := json.Marshal(times)
+	if err != nil {
+		return fmt.Errorf("encode heartbeats: %w", err)
+	}
+
+	return writeFileAtomic(f.path, data)
+}
+
+// Load returns the times all stored heartbeats were last seen.
+func (f *FileHeartbeatStore) Load(_ context.Context) (map[string]time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.read()
+}
@@ -0,0 +1,162 @@
+package notify
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchdog(t *testing.T) {
+	t.Parallel()
+
+	ch := make(logNotifier, 10)
+	watchdog := NewWatchdog(ch, WatchdogOptions{
+		Heartbeats: []Heartbeat{
+			{Name: "backup", Interval: 50 * time.Millisecond, Grace: 50 * time.Millisecond},
+		},
+	})
+	require.NoError(t, watchdog.Start(context.Background()))
+	defer watchdog.Stop()
+
+	// Regular pings keep the heartbeat alive.
+	for range 3 {
+		time.Sleep(30 * time.Millisecond)
+		require.NoError(t, watchdog.Ping("backup"))
+	}
+	requireNoLog(t, ch)
+
+	got := receiveLog(t, ch)
+	require.Equal(t, `Heartbeat "backup" missed`, got.subject)
+	require.Contains(t, got.message, "No ping received since ")
+	require.Contains(t, got.message, "Expected every 50ms with a grace period of 50ms.")
+	require.Equal(t, SeverityError, got.severity)
+	require.True(t, watchdog.Status()[0].Down)
+
+	// A missed heartbeat is only reported once.
+	requireNoLog(t, ch)
+
+	require.NoError(t, watchdog.Ping("backup"))
+	got = receiveLog(t, ch)
+	require.Equal(t, `Heartbeat "backup" recovered`, got.subject)
+	require.Equal(t, SeverityInfo, got.severity)
+	require.False(t, watchdog.Status()[0].Down)
+}
+
+func TestWatchdog_NeverSeen(t *testing.T) {
+	t.Parallel()
+
+	ch := make(logNotifier, 10)
+	watchdog := NewWatchdog(ch, WatchdogOptions{
+		Heartbeats: []Heartbeat{{Name: "sync", Interval: 20 * time.Millisecond}},
+	})
+	require.NoError(t, watchdog.Start(context.Background()))
+	defer watchdog.Stop()
+
+	got := receiveLog(t, ch)
+	require.Equal(t, `Heartbeat "sync" missed`, got.subject)
+	require.Contains(t, got.message, "No ping received since the watchdog started at ")
+	require.True(t, watchdog.Status()[0].LastSeen.IsZero())
+}
+
+func TestWatchdog_UnknownHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	watchdog := NewWatchdog(make(logNotifier), WatchdogOptions{})
+
+	require.ErrorIs(t, watchdog.Ping("nope"), ErrUnknownHeartbeat)
+}
+
+func TestWatchdog_Stop(t *testing.T) {
+	t.Parallel()
+
+	ch := make(logNotifier, 10)
+	watchdog := NewWatchdog(ch, WatchdogOptions{
+		Heartbeats: []Heartbeat{{Name: "sync", Interval: 20 * time.Millisecond}},
+	})
+	require.NoError(t, watchdog.Start(context.Background()))
+	watchdog.Stop()
+
+	requireNoLog(t, ch)
+}
+
+func TestWatchdog_Store(t *testing.T) {
+	t.Parallel()
+
+	store := NewFileHeartbeatStore(filepath.Join(t.TempDir(), "heartbeats.json"))
+	heartbeats := []Heartbeat{{Name: "backup", Interval: time.Hour}}
+
+	first := NewWatchdog(make(logNotifier), WatchdogOptions{Heartbeats: heartbeats, Store: store})
+	require.NoError(t, first.Start(context.Background()))
+	require.NoError(t, first.Ping("backup"))
+	first.Stop()
+
+	lastSeen, err := store.Load(context.Background())
+	require.NoError(t, err)
+	require.Contains(t, lastSeen, "backup")
+
+	// Older times don't overwrite newer ones.
+	require.NoError(t, store.Save(context.Background(), "backup", lastSeen["backup"].Add(-time.Minute)))
+
+	// A restarted watchdog picks up the last ping.
+	second := NewWatchdog(make(logNotifier), WatchdogOptions{Heartbeats: heartbeats, Store: store})
+	require.NoError(t, second.Start(context.Background()))
+	defer second.Stop()
+
+	require.True(t, lastSeen["backup"].Equal(second.Status()[0].LastSeen))
+}
+
+func TestWatchdog_MissedWhileStopped(t *testing.T) {
+	t.Parallel()
+
+	store := NewFileHeartbeatStore(filepath.Join(t.TempDir(), "heartbeats.json"))
+	lastSeen := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	require.NoError(t, store.Save(context.Background(), "backup", lastSeen))
+
+	ch := make(logNotifier, 10)
+	watchdog := NewWatchdog(ch, WatchdogOptions{
+		Heartbeats: []Heartbeat{{Name: "backup", Interval: time.Hour}},
+		Store:      store,
+	})
+	require.NoError(t, watchdog.Start(context.Background()))
+	defer watchdog.Stop()
+
+	got := receiveLog(t, ch)
+	require.Equal(t, `Heartbeat "backup" missed`, got.subject)
+	require.Contains(t, got.message, lastSeen.Format(time.RFC3339))
+}
+
+func TestWatchdog_ConcurrentStop(t *testing.T) {
+	t.Parallel()
+
+	heartbeats := make([]Heartbeat, 10)
+	for i := range heartbeats {
+		heartbeats[i] = Heartbeat{Name: strconv.Itoa(i), Interval: time.Hour}
+	}
+
+	ch := make(logNotifier, len(heartbeats))
+	watchdog := NewWatchdog(ch, WatchdogOptions{Heartbeats: heartbeats})
+	require.NoError(t, watchdog.Start(context.Background()))
+	for _, hb := range watchdog.heartbeats {
+		hb.down = true
+	}
+
+	var wg sync.WaitGroup
+	for _, hb := range heartbeats {
+		wg.Go(func() { assert.NoError(t, watchdog.Ping(hb.Name)) })
+	}
+	watchdog.Stop()
+	wg.Wait()
+
+	// Recoveries after Stop are no longer reported.
+	sent := len(ch)
+	watchdog.heartbeats["0"].down = true
+	require.NoError(t, watchdog.Ping("0"))
+	watchdog.Stop()
+	require.Len(t, ch, sent)
+}